@@ -79,7 +79,15 @@ table td {
 
 // I18n our i18n instance. We set it as package-level variable for the sake of the example.
 // Note that, we use 1 language in this example but you can extend to as many as you want.
-var I18n, _ = i18n.New(i18n.Glob("./locales/*/*"), "en-US")
+// The plural counts of this example are passed positionally, e.g. tr(w, r, "FreeDay", 5),
+// so the PositionalPluralCount option is enabled, see `i18n.PluralCount` for the alternative.
+var I18n, _ = i18n.New(i18n.Glob("./locales/*/*", loaderConfig()), "en-US")
+
+func loaderConfig() i18n.LoaderConfig {
+	options := i18n.DefaultLoaderConfig
+	options.PositionalPluralCount = true
+	return options
+}
 
 func main() {
 	router := http.NewServeMux()
@@ -15,7 +15,13 @@ const (
 	male
 )
 
-var I18n, _ = i18n.New(i18n.Glob("./locales/*/*"), "en-US")
+var I18n, _ = i18n.New(i18n.Glob("./locales/*/*", loaderConfig()), "en-US")
+
+func loaderConfig() i18n.LoaderConfig {
+	options := i18n.DefaultLoaderConfig
+	options.PositionalPluralCount = true
+	return options
+}
 
 func TestI18nPlurals(t *testing.T) {
 
@@ -0,0 +1,160 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The Android string resources elements which are decoded by the `unmarshalAndroidStrings`.
+type (
+	androidResources struct {
+		Strings []androidString `xml:"string"`
+		Plurals []androidPlural `xml:"plurals"`
+	}
+
+	androidString struct {
+		Name         string `xml:"name,attr"`
+		Translatable string `xml:"translatable,attr"`
+		Value        string `xml:",chardata"`
+	}
+
+	androidPlural struct {
+		Name  string `xml:"name,attr"`
+		Items []struct {
+			Quantity string `xml:"quantity,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"item"`
+	}
+)
+
+// unmarshalXML decodes the ".xml" locale files, only the Android string resources,
+// e.g. the "res/values-el/strings.xml", are supported, see `unmarshalAndroidStrings`.
+func unmarshalXML(data []byte, v interface{}) error {
+	if root := xmlRootElement(data); root != "resources" {
+		return fmt.Errorf("xml: unsupported root element: <%s>, expected an Android <resources>", root)
+	}
+
+	return unmarshalAndroidStrings(data, v)
+}
+
+// unmarshalAndroidStrings decodes an Android string resources file.
+// The <string> elements are stored by their name and the <plurals> ones
+// as plural messages of their <item> quantities, e.g. "one" and "other".
+// The untranslatable strings are skipped.
+//
+// The Android escape sequences are unescaped and the positional
+// format arguments are converted to the Go ones, e.g. "%1$s" to "%[1]s".
+func unmarshalAndroidStrings(data []byte, v interface{}) error {
+	var resources androidResources
+	if err := xml.Unmarshal(data, &resources); err != nil {
+		return fmt.Errorf("android: %w", err)
+	}
+
+	m := *v.(*map[string]interface{})
+
+	for _, s := range resources.Strings {
+		if s.Name == "" || s.Translatable == "false" {
+			continue
+		}
+
+		m[s.Name] = unescapeAndroidString(s.Value)
+	}
+
+	for _, p := range resources.Plurals {
+		if p.Name == "" || len(p.Items) == 0 {
+			continue
+		}
+
+		forms := make(Map, len(p.Items))
+		for _, item := range p.Items {
+			forms[item.Quantity] = unescapeAndroidString(item.Value)
+		}
+
+		m[p.Name] = forms
+	}
+
+	return nil
+}
+
+var androidFormatArgRegexp = regexp.MustCompile(`%(\d+)\$`)
+
+// unescapeAndroidString returns the text of an Android string resource.
+func unescapeAndroidString(s string) string {
+	s = strings.TrimSpace(s)
+
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1] // the quoted strings keep their white space.
+	} else {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch c := s[i]; c {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if i+4 < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteString(`\u`)
+		default: // e.g. \' \" \@ \? \\.
+			b.WriteByte(c)
+		}
+	}
+
+	return androidFormatArgRegexp.ReplaceAllString(b.String(), "%[$1]")
+}
+
+// androidLanguage returns the language code of an Android resources directory name,
+// e.g. "el" for the "values-el", "pt-BR" for the "values-pt-rBR"
+// and "sr-Latn" for the "values-b+sr+Latn".
+func androidLanguage(dir string) (string, bool) {
+	qualifiers := strings.TrimPrefix(dir, "values-")
+	if qualifiers == dir || qualifiers == "" {
+		return "", false
+	}
+
+	parts := strings.Split(qualifiers, "-")
+	if strings.HasPrefix(parts[0], "b+") { // BCP 47, e.g. b+sr+Latn.
+		return strings.ReplaceAll(parts[0][2:], "+", "-"), true
+	}
+
+	lang := parts[0]
+	if len(parts) > 1 && len(parts[1]) == 3 && parts[1][0] == 'r' {
+		lang += "-" + parts[1][1:]
+	}
+
+	return lang, true
+}
+
+// xmlRootElement returns the local name of the root element of an XML document.
+func xmlRootElement(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
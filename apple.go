@@ -0,0 +1,364 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// unmarshalAppleStrings decodes an Apple ".strings" file,
+// e.g. the "el.lproj/Localizable.strings", of `"key" = "value";` entries.
+// The Apple format specifiers are converted to the Go ones, e.g. "%1$@" to "%[1]v".
+func unmarshalAppleStrings(data []byte, v interface{}) error {
+	m := *v.(*map[string]interface{})
+
+	p := appleStringsParser{data: data, line: 1}
+	for {
+		p.skipSpaceAndComments()
+		if p.eof() {
+			return nil
+		}
+
+		key, err := p.token()
+		if err != nil {
+			return err
+		}
+
+		p.skipSpaceAndComments()
+		if err = p.expect('='); err != nil {
+			return err
+		}
+
+		p.skipSpaceAndComments()
+		value, err := p.token()
+		if err != nil {
+			return err
+		}
+
+		p.skipSpaceAndComments()
+		if err = p.expect(';'); err != nil {
+			return err
+		}
+
+		m[key] = convertAppleFormat(value)
+	}
+}
+
+type appleStringsParser struct {
+	data []byte
+	pos  int
+	line int
+}
+
+func (p *appleStringsParser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *appleStringsParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("strings: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *appleStringsParser) skipSpaceAndComments() {
+	for !p.eof() {
+		switch c := p.data[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case bytes.HasPrefix(p.data[p.pos:], []byte("//")):
+			for !p.eof() && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		case bytes.HasPrefix(p.data[p.pos:], []byte("/*")):
+			end := bytes.Index(p.data[p.pos+2:], []byte("*/"))
+			if end == -1 {
+				end = len(p.data) - p.pos - 2
+			} else {
+				end += 2
+			}
+
+			p.line += bytes.Count(p.data[p.pos:p.pos+2+end], []byte("\n"))
+			p.pos += 2 + end
+		default:
+			return
+		}
+	}
+}
+
+func (p *appleStringsParser) expect(c byte) error {
+	if p.eof() || p.data[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+
+	p.pos++
+	return nil
+}
+
+// token reads a quoted string or an unquoted word.
+func (p *appleStringsParser) token() (string, error) {
+	if p.eof() {
+		return "", p.errorf("unexpected end of file")
+	}
+
+	if p.data[p.pos] != '"' {
+		start := p.pos
+		for !p.eof() && isAppleWordByte(p.data[p.pos]) {
+			p.pos++
+		}
+
+		if start == p.pos {
+			return "", p.errorf("unexpected %q", p.data[p.pos])
+		}
+
+		return string(p.data[start:p.pos]), nil
+	}
+
+	p.pos++ // the opening quote.
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.data[p.pos]
+		p.pos++
+
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\n':
+			p.line++
+			b.WriteByte(c)
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+
+			c = p.data[p.pos]
+			p.pos++
+
+			switch c {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				if p.pos+4 > len(p.data) {
+					return "", p.errorf("invalid unicode escape")
+				}
+
+				r, err := strconv.ParseUint(string(p.data[p.pos:p.pos+4]), 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default: // e.g. \" and \\.
+				b.WriteByte(c)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func isAppleWordByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c == '$' || c == '/' || c == ':' ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c >= utf8.RuneSelf
+}
+
+var appleFormatRegexp = regexp.MustCompile(`%(\d+\$)?(l{0,2}|h{0,2}|q|z|t|j)([@dDiuUxXoOfeEgGcCsSp%])`)
+
+// convertAppleFormat converts the Apple format specifiers of "s" to the Go ones,
+// e.g. "%@" to "%v", "%1$@" to "%[1]v" and "%ld" to "%d".
+func convertAppleFormat(s string) string {
+	if strings.IndexByte(s, '%') == -1 {
+		return s
+	}
+
+	return appleFormatRegexp.ReplaceAllStringFunc(s, func(spec string) string {
+		sub := appleFormatRegexp.FindStringSubmatch(spec)
+		position, verb := sub[1], sub[3]
+
+		switch verb {
+		case "@", "s", "S", "p":
+			verb = "v"
+		case "D", "i", "u", "U":
+			verb = "d"
+		case "O":
+			verb = "o"
+		case "C":
+			verb = "c"
+		case "%":
+			return "%%"
+		}
+
+		if position != "" {
+			return "%[" + strings.TrimSuffix(position, "$") + "]" + verb
+		}
+
+		return "%" + verb
+	})
+}
+
+var appleVariableRegexp = regexp.MustCompile(`%#@([^@]+)@`)
+
+// unmarshalStringsDict decodes an Apple ".stringsdict" property list file.
+// The plural rule variants of each key, e.g. "one" and "other",
+// are stored as its plural forms, so they are selected by the CLDR plural
+// categories of the language, e.g. through the `TrPlural`.
+// The variable of the "NSStringLocalizedFormatKey", e.g. "%#@count@",
+// is replaced by each one of its plural variants.
+// Only the first variable of a format is resolved.
+func unmarshalStringsDict(data []byte, v interface{}) error {
+	root, err := decodePlist(data)
+	if err != nil {
+		return fmt.Errorf("stringsdict: %w", err)
+	}
+
+	entries, ok := root.(map[string]interface{})
+	if !ok {
+		return errors.New("stringsdict: expected a root dictionary")
+	}
+
+	m := *v.(*map[string]interface{})
+
+	for key, value := range entries {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		format, _ := entry["NSStringLocalizedFormatKey"].(string)
+		match := appleVariableRegexp.FindStringSubmatchIndex(format)
+		if match == nil {
+			m[key] = convertAppleFormat(format)
+			continue
+		}
+
+		variable, ok := entry[format[match[2]:match[3]]].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("stringsdict: %s: missing variable: %s", key, format[match[2]:match[3]])
+		}
+
+		forms := make(Map)
+		for category, form := range variable {
+			if strings.HasPrefix(category, "NSStringFormat") {
+				continue
+			}
+
+			if text, ok := form.(string); ok {
+				forms[category] = convertAppleFormat(format[:match[0]] + text + format[match[1]:])
+			}
+		}
+
+		m[key] = forms
+	}
+
+	return nil
+}
+
+// decodePlist decodes an XML property list of dictionaries and strings,
+// the rest of the values are decoded as their text.
+func decodePlist(data []byte) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("empty property list")
+			}
+
+			return nil, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return decodePlistValue(decoder, start)
+		}
+	}
+}
+
+func decodePlistValue(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := make(map[string]interface{})
+		var key string
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			switch t := token.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err = decoder.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
+				value, err := decodePlistValue(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+
+				dict[key] = value
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var array []interface{}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			switch t := token.(type) {
+			case xml.StartElement:
+				value, err := decodePlistValue(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+
+				array = append(array, value)
+			case xml.EndElement:
+				return array, nil
+			}
+		}
+	default:
+		var text string
+		err := decoder.DecodeElement(&text, &start)
+		return text, err
+	}
+}
+
+// appleLanguage returns the language of an Apple localization directory of the "path",
+// e.g. "el" for the "el.lproj/Localizable.strings" and "pt-BR" for the "pt_BR.lproj".
+func appleLanguage(path string) (string, bool) {
+	idx := strings.Index(path, ".lproj")
+	if idx == -1 {
+		return "", false
+	}
+
+	dir := path[:idx]
+	if start := strings.LastIndexFunc(dir, isPathSeparator); start >= 0 {
+		dir = dir[start+1:]
+	}
+
+	return strings.ReplaceAll(dir, "_", "-"), dir != ""
+}
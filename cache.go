@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"container/list"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// matchCacheSize is the maximum number of the cached language matches.
+	matchCacheSize = 512
+	// matchCacheMaxKeyLen is the maximum length of a cached input,
+	// longer inputs, e.g. adversarial headers, are matched without the cache.
+	matchCacheMaxKeyLen = 128
+)
+
+// matchResult is a cached result of the `I18n.match`.
+type matchResult struct {
+	tag   language.Tag
+	index int
+	conf  language.Confidence
+}
+
+// matchCacheKey is the key of a cached input,
+// the "acceptLanguage" separates the Accept-Language values from the language codes.
+type matchCacheKey struct {
+	input          string
+	acceptLanguage bool
+}
+
+type matchCacheEntry struct {
+	key    matchCacheKey
+	result matchResult
+}
+
+// matchCache is a bounded least-recently-used cache of the matched language inputs,
+// e.g. the raw Accept-Language header values, cookie and URL parameter values.
+// A new one is created on each `I18n.Reload` and when a language is added
+// because the language indexes may change.
+type matchCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[matchCacheKey]*list.Element
+	order   *list.List // front is the most recently used.
+}
+
+func newMatchCache(size int) *matchCache {
+	return &matchCache{
+		size:    size,
+		entries: make(map[matchCacheKey]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+func (c *matchCache) get(key matchCacheKey) (matchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return matchResult{}, false
+	}
+
+	c.order.MoveToFront(elem)
+	return elem.Value.(*matchCacheEntry).result, true
+}
+
+func (c *matchCache) set(key matchCacheKey, result matchResult) {
+	if len(key.input) > matchCacheMaxKeyLen {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*matchCacheEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		// evict the least recently used.
+		if oldest := c.order.Back(); oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*matchCacheEntry).key)
+		}
+	}
+
+	c.entries[key] = c.order.PushFront(&matchCacheEntry{key: key, result: result})
+}
+
+// cachedMatch returns the cached match result of the "key"
+// or calls and caches the result of the "match" function.
+func (i *I18n) cachedMatch(key matchCacheKey, match func() (language.Tag, int, language.Confidence)) (language.Tag, int, language.Confidence) {
+	// get the cache before matching, so a result of a previous matcher
+	// is never stored to the cache of a reloaded one.
+	i.mu.RLock()
+	cache := i.matchCache
+	i.mu.RUnlock()
+
+	if cache == nil || i.DisableMatchCache {
+		return match()
+	}
+
+	if result, ok := cache.get(key); ok {
+		return result.tag, result.index, result.conf
+	}
+
+	tag, index, conf := match()
+	cache.set(key, matchResult{tag: tag, index: index, conf: conf})
+	return tag, index, conf
+}
@@ -0,0 +1,182 @@
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/kataras/i18n/internal"
+)
+
+// DuplicateKey describes what to do when a translation key is defined more than once
+// for the same language, e.g. by different loaders of `ChainWith`
+// or different locale files, see `LoaderConfig.OnDuplicateKey`.
+type DuplicateKey = internal.DuplicateKey
+
+const (
+	// DuplicateKeyOverride silently overrides the previous value with the last one.
+	DuplicateKeyOverride = internal.DuplicateKeyOverride
+	// DuplicateKeyWarn overrides the previous value with the last one and logs a warning.
+	DuplicateKeyWarn = internal.DuplicateKeyWarn
+	// DuplicateKeyError fails the loading.
+	DuplicateKeyError = internal.DuplicateKeyError
+)
+
+// ErrDuplicateKey is reported when a key is defined more than once
+// for the same language and the `DuplicateKeyError` is set.
+var ErrDuplicateKey = internal.ErrDuplicateKey
+
+// Chain returns a Loader which runs each one of the "loaders" against the same `Matcher`
+// and merges their translations. Later loaders override the keys of the earlier ones
+// for the same language.
+//
+// Example Code:
+//
+//	pluginLoader, err := i18n.FS(pluginFS, "./locales/*/*")
+//	// [handle error...]
+//	I18n, err := i18n.New(i18n.Chain(i18n.Glob("./core/*/*"), pluginLoader))
+//
+// See `ChainWith` too.
+func Chain(loaders ...Loader) Loader {
+	return ChainWith(DuplicateKeyOverride, loaders...)
+}
+
+// ChainWith same as `Chain` but it accepts the action
+// to take when a key is defined by more than one loader for the same language.
+func ChainWith(onDuplicate DuplicateKey, loaders ...Loader) Loader {
+	return func(m *Matcher) (Localizer, error) {
+		if len(loaders) == 0 {
+			return nil, fmt.Errorf("chain: no loaders")
+		}
+
+		localizers := make([]Localizer, 0, len(loaders))
+		for _, loader := range loaders {
+			localizer, err := loader(m)
+			if err != nil {
+				return nil, err
+			}
+
+			localizers = append(localizers, localizer)
+		}
+
+		return mergeLocalizers(m, "", onDuplicate, localizers...)
+	}
+}
+
+// Namespace returns a Loader which prefixes the keys of the "loader" with the "prefix",
+// e.g. with a prefix of "admin" the "title" key is translated through the "admin.title" key.
+// Nested namespaces concatenate their prefixes, from the outer to the inner one.
+// Template messages of the "loader" can still reference their own keys
+// without the prefix, e.g. {{tr "title"}}.
+//
+// Useful to avoid key collisions when different sources are merged through `Chain`.
+func Namespace(prefix string, loader Loader) Loader {
+	prefix = strings.TrimSuffix(prefix, ".")
+
+	return func(m *Matcher) (Localizer, error) {
+		localizer, err := loader(m)
+		if err != nil {
+			return nil, err
+		}
+
+		if prefix == "" {
+			return localizer, nil
+		}
+
+		return mergeLocalizers(m, prefix+".", DuplicateKeyOverride, localizer)
+	}
+}
+
+// mergeLocalizers returns a new Localizer which contains the messages of all "localizers",
+// their keys are prefixed by "keyPrefix".
+func mergeLocalizers(m *Matcher, keyPrefix string, onDuplicate DuplicateKey, localizers ...Localizer) (*chainLocalizer, error) {
+	options := DefaultLoaderConfig
+	options.DefaultMessageFunc = m.defaultMessageFunc
+	options.PluralFunc = m.pluralFunc
+	if loc := localizers[0].GetLocale(0); loc != nil {
+		options = loc.Options // e.g. keep the NoFallbackPrefixes.
+	}
+
+	// the loaders may have added languages, so create it after all of them.
+	cat, err := internal.NewCatalog(m.Languages, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, merged := range cat.Locales {
+		index := merged.Index()
+
+		for _, localizer := range localizers {
+			loc := localizer.GetLocale(index)
+			if loc == nil || loc.Index() != index {
+				continue // the localizer returned its default one.
+			}
+
+			for key, renderer := range loc.Messages {
+				key = keyPrefix + key
+
+				if _, exists := merged.Messages[key]; exists {
+					switch onDuplicate {
+					case DuplicateKeyError:
+						return nil, fmt.Errorf("chain: %s: %w: %s", merged.Language(), ErrDuplicateKey, key)
+					case DuplicateKeyWarn:
+						log.Printf("i18n: chain: %s: key %q is overridden", merged.Language(), key)
+					}
+				}
+
+				// the renderer keeps its own locale for printer and template functions.
+				merged.Messages[key] = renderer
+			}
+
+			internal.CopyMeta(merged, loc, keyPrefix)
+		}
+	}
+
+	l := &chainLocalizer{
+		Catalog:    cat,
+		localizers: localizers,
+	}
+	return l, nil
+}
+
+// chainLocalizer is the Localizer which the `Chain` and `Namespace` loaders return.
+type chainLocalizer struct {
+	*internal.Catalog
+	localizers []Localizer
+}
+
+// watch watches the chained localizers, each one keeps the file watcher
+// of the one of the "prev" localizer at the same position, if any.
+func (l *chainLocalizer) watch(prev Localizer, reload func() error) error {
+	p, _ := prev.(*chainLocalizer)
+
+	for idx, localizer := range l.localizers {
+		if w, ok := localizer.(watchableLocalizer); ok {
+			var prevLocalizer Localizer
+			if p != nil && idx < len(p.localizers) {
+				prevLocalizer = p.localizers[idx]
+			}
+
+			if err := w.watch(prevLocalizer, reload); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes the chained localizers.
+func (l *chainLocalizer) Close() error {
+	var firstErr error
+	for _, localizer := range l.localizers {
+		if c, ok := localizer.(io.Closer); ok {
+			if err := c.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,142 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kataras/i18n/internal"
+)
+
+// CSV is a loader which reads the translations of all languages from a single
+// CSV file, e.g. an export of a spreadsheet, with the columns "key,en-US,el-GR,...".
+// The first row is the header, its first column is ignored and the rest
+// are the language codes which are matched with the registered languages.
+// The first column of the rest rows is the key, dotted keys, e.g. "nav.home",
+// are looked up the same as nested yaml values. Empty cells are skipped,
+// so those keys fallback to the default language.
+//
+// The quoted fields may contain the delimiter and new lines.
+// The delimiter is ',', or '\t' for the ".tsv" files, see `LoaderConfig.CSVDelimiter`.
+//
+// See `New` and `LoaderConfig` too.
+func CSV(path string, opts ...LoaderConfig) Loader {
+	return func(m *Matcher) (Localizer, error) {
+		options := DefaultLoaderConfig
+		if len(opts) > 0 {
+			options = opts[0]
+		}
+
+		if options.DefaultMessageFunc == nil {
+			options.DefaultMessageFunc = m.defaultMessageFunc
+		}
+
+		if options.PluralFunc == nil {
+			options.PluralFunc = m.pluralFunc
+		}
+
+		data, err := os.ReadFile(path)
+		if err == nil {
+			data, err = decodeFile(data)
+		}
+		if err != nil {
+			return nil, &LoadError{File: path, Err: err}
+		}
+
+		delimiter := options.CSVDelimiter
+		if delimiter == 0 {
+			delimiter = ','
+			if strings.HasSuffix(path, ".tsv") {
+				delimiter = '\t'
+			}
+		}
+
+		langIndexes, keyValues, err := parseCSV(m, data, delimiter)
+		if err != nil {
+			return nil, &LoadError{File: path, Line: errorLine(data, err), Err: err}
+		}
+
+		cat, err := internal.NewCatalog(m.Languages, options)
+		if err != nil {
+			return nil, err
+		}
+
+		var errs []error
+		for column, langIndex := range langIndexes {
+			if langIndex == -1 {
+				continue // not a registered language.
+			}
+
+			if err = cat.Store(langIndex, keyValues[column]); err != nil {
+				errs = append(errs, &LoadError{File: path, Lang: m.Languages[langIndex].String(), Err: err})
+			}
+		}
+
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+
+		if n := len(cat.Locales); n == 0 {
+			return nil, fmt.Errorf("locales not found in %s", path)
+		} else if options.Strict && n < len(m.Languages) {
+			return nil, fmt.Errorf("locales expected to be %d but %d parsed", len(m.Languages), n)
+		}
+
+		return cat, nil
+	}
+}
+
+// parseCSV decodes the CSV "data" and returns the language index
+// and the key-value pairs of each language column.
+func parseCSV(m *Matcher, data []byte, delimiter rune) ([]int, []Map, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1 // the trailing empty cells may be missing.
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("csv: header: %w", err)
+	}
+
+	if len(header) < 2 {
+		return nil, nil, fmt.Errorf("csv: header: expected a key and at least one language column")
+	}
+
+	langIndexes := make([]int, len(header)-1)
+	keyValues := make([]Map, len(header)-1)
+	for column, languageName := range header[1:] {
+		languageName = strings.TrimSpace(languageName)
+		langIndexes[column] = parseLanguageName(m, languageName) // matches and adds the language tag to m.Languages.
+		keyValues[column] = make(Map)
+	}
+
+	for {
+		record, err := r.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, nil, fmt.Errorf("csv: %w", err)
+		}
+
+		key := strings.TrimSpace(record[0])
+		if key == "" {
+			continue
+		}
+
+		for column, value := range record[1:] {
+			if column >= len(keyValues) || value == "" {
+				continue
+			}
+
+			keyValues[column][key] = value
+		}
+	}
+
+	return langIndexes, keyValues, nil
+}
@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"bytes"
+	"errors"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// decodeFile returns the contents of a locale file as UTF-8 without a byte order mark,
+// e.g. of the files saved by Windows editors as UTF-8 with BOM
+// or the UTF-16 ones, with a BOM, exported by spreadsheets.
+func decodeFile(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return data[len(utf8BOM):], nil
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return decodeUTF16(data[len(utf16LEBOM):], false)
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return decodeUTF16(data[len(utf16BEBOM):], true)
+	default:
+		return data, nil
+	}
+}
+
+// decodeUTF16 converts the UTF-16 "data", without its BOM, to UTF-8.
+func decodeUTF16(data []byte, bigEndian bool) ([]byte, error) {
+	if len(data)%2 != 0 {
+		return nil, errors.New("utf-16: odd length")
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+
+	runes := utf16.Decode(units)
+	out := make([]byte, 0, len(runes))
+	for _, r := range runes {
+		out = utf8.AppendRune(out, r)
+	}
+
+	return out, nil
+}
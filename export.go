@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kataras/i18n/internal"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Export is package-level function which calls the `Default.Export` method.
+//
+// See `I18n#Export` method for more.
+func Export(lang, format string, w io.Writer) error {
+	return Default.Export(lang, format, w)
+}
+
+// Export writes the translation messages of the "lang" language to "w",
+// it is the inverse of loading, e.g. to normalize many locale files into one
+// or to send the current translations to the translators.
+// The "format" is one of the "yaml" (or "yml"), "json" and "toml".
+//
+// The messages are the merged ones of all the locale files, including the
+// ones set by `SetMessages` and the current `Overlay` ones.
+// Their dotted keys are nested and the plural messages are maps of their forms,
+// e.g. {"one":"%d item","other":"%d items"}.
+// The shared "Vars" are not exported, the messages keep their ${var} literals.
+//
+// It returns an error if "lang" not matched or the "format" is not supported.
+func (i *I18n) Export(lang, format string, w io.Writer) error {
+	_, index, ok := i.TryMatchString(lang)
+	if !ok {
+		return fmt.Errorf("export: language not found: %s", lang)
+	}
+
+	loc := i.getLocaleByIndex(index)
+	if loc == nil || loc.Index() != index {
+		return fmt.Errorf("export: language not loaded: %s", lang)
+	}
+
+	messages := loc.RawMessages()
+
+	i.mu.RLock()
+	layers := i.overlays[*loc.Tag()]
+	i.mu.RUnlock()
+
+	for _, layer := range layers {
+		for key, value := range layer.messages {
+			messages[key] = value
+		}
+	}
+
+	tree := internal.NestMessages(messages)
+
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "yaml", "yml":
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(tree); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(tree)
+	case "toml", "tml":
+		return toml.NewEncoder(w).Encode(tree)
+	default:
+		return fmt.Errorf("export: unsupported format: %s", format)
+	}
+}
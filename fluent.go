@@ -0,0 +1,487 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// unmarshalFluent decodes a Fluent (https://projectfluent.org) ".ftl" file.
+// The messages are compiled to template messages of the default "{{" and "}}" delimiters:
+//   - the { $name } placeables to {{.name}}, rendered by the template data, e.g. Tr("en-US", "hello", Map{"name": "kataras"}),
+//   - the message and term references, e.g. { -brand-name }, to the {{tr "-brand-name" .}} references,
+//   - the NUMBER($x) and DATETIME($x) functions to the {{number .x}} and {{date .x "medium"}} ones,
+//   - the selectors to {{if}} conditions, the numeric variant keys match the exact value,
+//     the CLDR plural categories, e.g. [one], match through the {{pluralCategory}} of the value
+//     and the rest match the value's text, e.g. the [male] of a { $gender -> ... } selector.
+//
+// The attributes of a message are stored as "message.attribute" keys, e.g. "login.title".
+func unmarshalFluent(data []byte, v interface{}) error {
+	m := *v.(*map[string]interface{})
+
+	entries, err := parseFluentEntries(string(data))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.value.text != "" {
+			value, err := compileFluentPattern(entry.value.text)
+			if err != nil {
+				return fmt.Errorf("ftl: line %d: %s: %w", entry.value.line, entry.id, err)
+			}
+
+			m[entry.id] = value
+		}
+
+		for _, attr := range entry.attributes {
+			value, err := compileFluentPattern(attr.text)
+			if err != nil {
+				return fmt.Errorf("ftl: line %d: %s.%s: %w", attr.line, entry.id, attr.name, err)
+			}
+
+			m[entry.id+"."+attr.name] = value
+		}
+	}
+
+	return nil
+}
+
+type (
+	fluentEntry struct {
+		id         string
+		value      fluentPattern
+		attributes []fluentPattern
+	}
+
+	// fluentPattern is the raw text of a message value or attribute.
+	fluentPattern struct {
+		name  string // the attribute's name.
+		text  string
+		line  int
+		lines []string // the indented lines, without the inline one.
+	}
+)
+
+var (
+	fluentEntryRegexp     = regexp.MustCompile(`^(-?[a-zA-Z][a-zA-Z0-9_-]*)\s*=\s*(.*)$`)
+	fluentAttributeRegexp = regexp.MustCompile(`^\.([a-zA-Z][a-zA-Z0-9_-]*)\s*=\s*(.*)$`)
+	fluentNumberRegexp    = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	fluentIdentRegexp     = regexp.MustCompile(`^-?[a-zA-Z][a-zA-Z0-9_-]*(\.[a-zA-Z][a-zA-Z0-9_-]*)?$`)
+	fluentFunctionRegexp  = regexp.MustCompile(`^([A-Z][A-Z0-9_-]*)\((.*)\)$`)
+	goIdentRegexp         = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// parseFluentEntries splits the "src" to its messages, terms and their attributes.
+func parseFluentEntries(src string) ([]*fluentEntry, error) {
+	var (
+		entries []*fluentEntry
+		entry   *fluentEntry
+		pattern *fluentPattern // the current value or attribute.
+	)
+
+	endPattern := func() {
+		if pattern != nil {
+			pattern.text = joinFluentLines(pattern.text, pattern.lines)
+			pattern = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lineNumber := i + 1
+
+		if strings.TrimSpace(line) == "" {
+			if pattern != nil {
+				pattern.lines = append(pattern.lines, "")
+			}
+			continue
+		}
+
+		// the closing brace of a multiline placeable may start a line too.
+		if line[0] == ' ' || line[0] == '\t' || (line[0] == '}' && fluentBraceDepth(pattern) > 0) {
+			if entry == nil {
+				return nil, fmt.Errorf("ftl: line %d: unexpected indented line", lineNumber)
+			}
+
+			trimmed := strings.TrimSpace(line)
+			if matches := fluentAttributeRegexp.FindStringSubmatch(trimmed); len(matches) == 3 && fluentBraceDepth(pattern) == 0 {
+				endPattern()
+				entry.attributes = append(entry.attributes, fluentPattern{name: matches[1], text: matches[2], line: lineNumber})
+				pattern = &entry.attributes[len(entry.attributes)-1]
+				continue
+			}
+
+			pattern.lines = append(pattern.lines, line)
+			continue
+		}
+
+		endPattern()
+		entry = nil
+
+		if line[0] == '#' {
+			continue // a comment.
+		}
+
+		matches := fluentEntryRegexp.FindStringSubmatch(line)
+		if len(matches) != 3 {
+			return nil, fmt.Errorf("ftl: line %d: expected a message", lineNumber)
+		}
+
+		entry = &fluentEntry{id: matches[1], value: fluentPattern{text: matches[2], line: lineNumber}}
+		entries = append(entries, entry)
+		pattern = &entry.value
+	}
+
+	endPattern()
+	return entries, nil
+}
+
+// fluentBraceDepth reports the open placeables of the "pattern", so an indented
+// line which starts with a dot is an attribute only outside of them.
+func fluentBraceDepth(pattern *fluentPattern) int {
+	if pattern == nil {
+		return 0
+	}
+
+	text := pattern.text + strings.Join(pattern.lines, "\n")
+	return strings.Count(text, "{") - strings.Count(text, "}")
+}
+
+// joinFluentLines joins the inline text of a pattern with its indented lines,
+// their common indentation is removed.
+func joinFluentLines(inline string, lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || line[0] == '}' {
+			continue
+		}
+
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent == -1 || n < indent {
+			indent = n
+		}
+	}
+
+	parts := make([]string, 0, len(lines)+1)
+	if inline = strings.TrimSpace(inline); inline != "" {
+		parts = append(parts, inline)
+	}
+
+	for _, line := range lines {
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); n >= indent && indent > 0 {
+			line = line[indent:]
+		}
+
+		parts = append(parts, strings.TrimRight(line, " \t"))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// compileFluentPattern converts the placeables of a Fluent pattern to template actions.
+func compileFluentPattern(pattern string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '{':
+			end, err := fluentClosingBrace(pattern, i)
+			if err != nil {
+				return "", err
+			}
+
+			action, err := compileFluentExpression(pattern[i+1 : end])
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(action)
+			i = end
+		case '}':
+			return "", fmt.Errorf("unbalanced closing brace")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// fluentClosingBrace returns the index of the brace which closes the one at the "start".
+func fluentClosingBrace(s string, start int) (int, error) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			// skip the string literal.
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return -1, fmt.Errorf("unclosed placeable")
+}
+
+// compileFluentExpression converts the content of a placeable to template actions or text.
+func compileFluentExpression(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+
+	if idx := fluentSelectorIndex(expr); idx != -1 {
+		return compileFluentSelector(strings.TrimSpace(expr[:idx]), expr[idx+2:])
+	}
+
+	switch {
+	case strings.HasPrefix(expr, `"`):
+		return unquoteFluent(expr)
+	case fluentNumberRegexp.MatchString(expr):
+		return expr, nil
+	case strings.HasPrefix(expr, "{"): // a nested placeable.
+		end, err := fluentClosingBrace(expr, 0)
+		if err != nil {
+			return "", err
+		}
+
+		return compileFluentExpression(expr[1:end])
+	}
+
+	operand, err := fluentOperand(expr)
+	if err != nil {
+		return "", err
+	}
+
+	return "{{" + operand + "}}", nil
+}
+
+// fluentOperand converts a variable, function or reference expression to a template pipeline.
+func fluentOperand(expr string) (string, error) {
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		name := expr[1:]
+		if goIdentRegexp.MatchString(name) {
+			return "." + name, nil
+		}
+
+		return fmt.Sprintf("index . %q", name), nil
+	case fluentFunctionRegexp.MatchString(expr):
+		matches := fluentFunctionRegexp.FindStringSubmatch(expr)
+		arg := strings.TrimSpace(strings.SplitN(matches[2], ",", 2)[0]) // the named options are ignored.
+		if !strings.HasPrefix(arg, "$") {
+			return "", fmt.Errorf("unsupported function argument: %s", expr)
+		}
+
+		operand, err := fluentOperand(arg)
+		if err != nil {
+			return "", err
+		}
+
+		switch matches[1] {
+		case "NUMBER":
+			return "number (" + operand + ")", nil
+		case "DATETIME":
+			return "date (" + operand + `) "medium"`, nil
+		default:
+			return "", fmt.Errorf("unsupported function: %s", matches[1])
+		}
+	}
+
+	ref := expr
+	if idx := strings.IndexByte(ref, '('); idx > 0 && strings.HasSuffix(ref, ")") {
+		ref = strings.TrimSpace(ref[:idx]) // the term arguments are ignored.
+	}
+
+	if fluentIdentRegexp.MatchString(ref) {
+		return fmt.Sprintf("tr %q .", ref), nil
+	}
+
+	return "", fmt.Errorf("unsupported expression: %s", expr)
+}
+
+// fluentSelectorIndex returns the index of the "->" of a select expression, or -1.
+func fluentSelectorIndex(expr string) int {
+	depth := 0
+	for i := 0; i < len(expr)-1; i++ {
+		switch expr[i] {
+		case '"':
+			for i++; i < len(expr) && expr[i] != '"'; i++ {
+				if expr[i] == '\\' {
+					i++
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '-':
+			if depth == 0 && expr[i+1] == '>' {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+type fluentVariant struct {
+	key       string
+	pattern   string
+	isDefault bool
+}
+
+// compileFluentSelector converts a select expression to {{if}} conditions.
+func compileFluentSelector(selector, body string) (string, error) {
+	operand, err := fluentOperand(selector)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(operand, "tr ") {
+		return "", fmt.Errorf("unsupported selector: %s", selector)
+	}
+
+	variants, err := parseFluentVariants(body)
+	if err != nil {
+		return "", err
+	}
+
+	var defaultVariant *fluentVariant
+	for i := range variants {
+		if variants[i].isDefault {
+			defaultVariant = &variants[i]
+		}
+	}
+	if defaultVariant == nil {
+		return "", fmt.Errorf("selector without a default variant: %s", selector)
+	}
+
+	// the exact numbers match first.
+	sort.SliceStable(variants, func(i, j int) bool {
+		return fluentNumberRegexp.MatchString(variants[i].key) && !fluentNumberRegexp.MatchString(variants[j].key)
+	})
+
+	var b strings.Builder
+	conditions := 0
+	for _, variant := range variants {
+		if variant.isDefault {
+			continue
+		}
+
+		pattern, err := compileFluentPattern(variant.pattern)
+		if err != nil {
+			return "", err
+		}
+
+		keyword := "{{if "
+		if conditions > 0 {
+			keyword = "{{else if "
+		}
+		conditions++
+
+		if _, ok := pluralCategoryNames[variant.key]; ok {
+			fmt.Fprintf(&b, "%seq (pluralCategory (%s)) %q}}%s", keyword, operand, variant.key, pattern)
+		} else {
+			fmt.Fprintf(&b, "%seq (print (%s)) %q}}%s", keyword, operand, variant.key, pattern)
+		}
+	}
+
+	pattern, err := compileFluentPattern(defaultVariant.pattern)
+	if err != nil {
+		return "", err
+	}
+
+	if conditions == 0 {
+		return pattern, nil
+	}
+
+	b.WriteString("{{else}}")
+	b.WriteString(pattern)
+	b.WriteString("{{end}}")
+	return b.String(), nil
+}
+
+// pluralCategoryNames are the CLDR plural categories, the variant keys of the plural selectors.
+var pluralCategoryNames = map[string]struct{}{
+	"zero": {}, "one": {}, "two": {}, "few": {}, "many": {}, "other": {},
+}
+
+// parseFluentVariants parses the "[key] pattern" variants of a select expression,
+// the default one starts with a star, e.g. "*[other] pattern".
+func parseFluentVariants(body string) ([]fluentVariant, error) {
+	var variants []fluentVariant
+
+	i := 0
+	for {
+		for i < len(body) && strings.ContainsRune(" \t\n", rune(body[i])) {
+			i++
+		}
+		if i >= len(body) {
+			break
+		}
+
+		var variant fluentVariant
+		if body[i] == '*' {
+			variant.isDefault = true
+			i++
+		}
+
+		if i >= len(body) || body[i] != '[' {
+			return nil, fmt.Errorf("expected a variant key")
+		}
+
+		end := strings.IndexByte(body[i:], ']')
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed variant key")
+		}
+		variant.key = strings.TrimSpace(body[i+1 : i+end])
+		i += end + 1
+
+		// the pattern ends on the next line which starts a variant, outside of placeables.
+		start, depth := i, 0
+	scan:
+		for ; i < len(body); i++ {
+			switch body[i] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '\n':
+				if depth == 0 {
+					if next := strings.TrimLeft(body[i+1:], " \t"); strings.HasPrefix(next, "[") || strings.HasPrefix(next, "*[") {
+						break scan
+					}
+				}
+			}
+		}
+		variant.pattern = strings.TrimSpace(body[start:i])
+		variants = append(variants, variant)
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("selector without variants")
+	}
+
+	return variants, nil
+}
+
+// unquoteFluent returns the text of a Fluent string literal, e.g. "{" of { "{" }.
+func unquoteFluent(literal string) (string, error) {
+	s, err := strconv.Unquote(literal)
+	if err != nil {
+		return "", fmt.Errorf("invalid string literal: %s", literal)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,632 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/i18n/internal"
+)
+
+// gettextMessage is a single entry of a gettext .po or .mo file.
+type gettextMessage struct {
+	Context  string
+	ID       string
+	IDPlural string
+	Str      []string // msgstr or msgstr[n].
+	Fuzzy    bool
+	// Comment is the translator (#) comments and Extracted the extracted (#.) ones.
+	Comment   string
+	Extracted string
+}
+
+// unmarshalPO decodes a GNU gettext .po file.
+// The msgid is the translation key. Plural entries (msgid_plural and msgstr[n])
+// are selected based on the rule of the "Plural-Forms" header.
+// Fuzzy, obsolete and untranslated entries are skipped,
+// so they fall back to the default language.
+func unmarshalPO(data []byte, v interface{}) error {
+	messages, err := parsePO(data)
+	if err != nil {
+		return err
+	}
+
+	return storeGettextMessages(messages, v)
+}
+
+// unmarshalMO decodes a GNU gettext binary .mo file,
+// see `unmarshalPO` for more.
+func unmarshalMO(data []byte, v interface{}) error {
+	messages, err := parseMO(data)
+	if err != nil {
+		return err
+	}
+
+	return storeGettextMessages(messages, v)
+}
+
+func storeGettextMessages(messages []*gettextMessage, v interface{}) error {
+	m := *v.(*map[string]interface{})
+
+	rule := defaultGettextPluralRule
+	for _, msg := range messages {
+		if msg.ID == "" && msg.Context == "" && len(msg.Str) > 0 { // the header.
+			if expr, ok := gettextHeader(msg.Str[0], "Plural-Forms"); ok {
+				r, err := parseGettextPluralForms(expr)
+				if err != nil {
+					return err
+				}
+				rule = r
+			}
+			break
+		}
+	}
+
+	meta := make(internal.MetaMap)
+	for _, msg := range messages {
+		if msg.ID == "" || msg.Fuzzy {
+			// Header or fuzzy entries.
+			continue
+		}
+
+		key := msg.ID
+		if msg.Context != "" { // see `Locale.GetMessageContext`.
+			key += internal.ContextSeparator + msg.Context
+		}
+
+		if msg.Comment != "" || msg.Extracted != "" || msg.Context != "" {
+			meta[key] = internal.MessageMeta{Description: msg.Extracted, Context: msg.Context, Comment: msg.Comment}
+		}
+
+		if msg.IDPlural == "" {
+			if len(msg.Str) == 0 || msg.Str[0] == "" {
+				continue // untranslated.
+			}
+
+			m[key] = msg.Str[0]
+			continue
+		}
+
+		plurals := make([]internal.PluralValue, 0, len(msg.Str))
+		for index, str := range msg.Str {
+			if str == "" {
+				continue
+			}
+
+			plurals = append(plurals, internal.PluralValue{
+				Form:  &gettextPluralForm{index: index, rule: rule},
+				Value: str,
+			})
+		}
+
+		if len(plurals) > 0 {
+			m[key] = plurals
+		}
+	}
+
+	if len(meta) > 0 {
+		m[internal.MetaKey] = meta
+	}
+
+	return nil
+}
+
+// gettextHeader returns the value of the "name" field of the header entry.
+func gettextHeader(header, name string) (string, bool) {
+	for _, line := range strings.Split(header, "\n") {
+		if idx := strings.IndexByte(line, ':'); idx > 0 && strings.EqualFold(strings.TrimSpace(line[:idx]), name) {
+			return strings.TrimSpace(line[idx+1:]), true
+		}
+	}
+
+	return "", false
+}
+
+func parsePO(data []byte) ([]*gettextMessage, error) {
+	var (
+		messages []*gettextMessage
+		msg      = new(gettextMessage)
+		current  *string // the field which continuation strings are appended to.
+		hasEntry bool
+	)
+
+	flush := func() {
+		if hasEntry {
+			messages = append(messages, msg)
+		}
+
+		msg = new(gettextMessage)
+		current = nil
+		hasEntry = false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#,"):
+			if len(msg.Str) > 0 {
+				flush()
+			}
+
+			for _, flag := range strings.Split(line[2:], ",") {
+				if strings.TrimSpace(flag) == "fuzzy" {
+					msg.Fuzzy = true
+				}
+			}
+			continue
+		case strings.HasPrefix(line, "#"):
+			// translator, extracted, reference and obsolete (#~) comments.
+			if len(msg.Str) > 0 {
+				flush()
+			}
+
+			switch {
+			case strings.HasPrefix(line, "#."):
+				msg.Extracted = appendLine(msg.Extracted, strings.TrimSpace(line[2:]))
+			case line == "#" || line[1] == ' ' || line[1] == '\t':
+				msg.Comment = appendLine(msg.Comment, strings.TrimSpace(line[1:]))
+			}
+			continue
+		case line[0] == '"':
+			if current == nil {
+				return nil, fmt.Errorf("po: line %d: unexpected string", lineNumber)
+			}
+
+			s, err := unquotePO(line)
+			if err != nil {
+				return nil, fmt.Errorf("po: line %d: %w", lineNumber, err)
+			}
+
+			*current += s
+			continue
+		}
+
+		keyword, value := line, ""
+		if idx := strings.IndexAny(line, " \t"); idx > 0 {
+			keyword, value = line[:idx], strings.TrimSpace(line[idx:])
+		}
+
+		s, err := unquotePO(value)
+		if err != nil {
+			return nil, fmt.Errorf("po: line %d: %w", lineNumber, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			if len(msg.Str) > 0 {
+				flush()
+			}
+			msg.Context = s
+			current = &msg.Context
+		case keyword == "msgid":
+			if len(msg.Str) > 0 {
+				flush()
+			}
+			msg.ID = s
+			current = &msg.ID
+		case keyword == "msgid_plural":
+			msg.IDPlural = s
+			current = &msg.IDPlural
+		case keyword == "msgstr":
+			msg.Str = []string{s}
+			current = &msg.Str[0]
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("po: line %d: invalid plural index: %s", lineNumber, keyword)
+			}
+
+			for len(msg.Str) <= index {
+				msg.Str = append(msg.Str, "")
+			}
+			msg.Str[index] = s
+			current = &msg.Str[index]
+		default:
+			return nil, fmt.Errorf("po: line %d: unexpected keyword: %s", lineNumber, keyword)
+		}
+
+		hasEntry = true
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+	return messages, nil
+}
+
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string but got: %s", s)
+	}
+
+	return strconv.Unquote(s)
+}
+
+// parseMO decodes the binary format of the .mo files,
+// see https://www.gnu.org/software/gettext/manual/html_node/MO-Files.html.
+func parseMO(data []byte) ([]*gettextMessage, error) {
+	if len(data) < 28 {
+		return nil, fmt.Errorf("mo: invalid file size")
+	}
+
+	var order binary.ByteOrder
+	switch binary.LittleEndian.Uint32(data) {
+	case 0x950412de:
+		order = binary.LittleEndian
+	case 0xde120495:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("mo: invalid magic number")
+	}
+
+	var (
+		count       = order.Uint32(data[8:])
+		originals   = order.Uint32(data[12:])
+		translation = order.Uint32(data[16:])
+	)
+
+	readString := func(tableOffset uint32, i uint32) (string, error) {
+		entry := uint64(tableOffset) + uint64(i)*8
+		if entry+8 > uint64(len(data)) {
+			return "", fmt.Errorf("mo: entry %d out of range", i)
+		}
+
+		length := uint64(order.Uint32(data[entry:]))
+		offset := uint64(order.Uint32(data[entry+4:]))
+		if offset+length > uint64(len(data)) {
+			return "", fmt.Errorf("mo: string %d out of range", i)
+		}
+
+		return string(data[offset : offset+length]), nil
+	}
+
+	var messages []*gettextMessage
+	for i := uint32(0); i < count; i++ {
+		id, err := readString(originals, i)
+		if err != nil {
+			return nil, err
+		}
+
+		str, err := readString(translation, i)
+		if err != nil {
+			return nil, err
+		}
+
+		msg := new(gettextMessage)
+		if idx := strings.IndexByte(id, '\x04'); idx >= 0 { // msgctxt EOT msgid.
+			msg.Context, id = id[:idx], id[idx+1:]
+		}
+
+		if idx := strings.IndexByte(id, '\x00'); idx >= 0 { // msgid NUL msgid_plural.
+			msg.ID, msg.IDPlural = id[:idx], id[idx+1:]
+			msg.Str = strings.Split(str, "\x00")
+		} else {
+			msg.ID = id
+			msg.Str = []string{str}
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// gettextPluralForm is the PluralForm of the msgstr[index] of a gettext plural entry.
+// It matches a count when the plural rule of the file evaluates to its index.
+type gettextPluralForm struct {
+	index int
+	rule  gettextPluralRule
+}
+
+var _ internal.PluralForm = (*gettextPluralForm)(nil)
+
+func (f *gettextPluralForm) String() string {
+	return "msgstr[" + strconv.Itoa(f.index) + "]"
+}
+
+func (f *gettextPluralForm) Less(next internal.PluralForm) bool {
+	if g, ok := next.(*gettextPluralForm); ok {
+		return f.index < g.index
+	}
+
+	return true
+}
+
+func (f *gettextPluralForm) MatchPlural(pluralCount int) bool {
+	return f.rule(pluralCount) == f.index
+}
+
+// gettextPluralRule returns the plural form index of "n".
+type gettextPluralRule func(n int) int
+
+// defaultGettextPluralRule is used when the "Plural-Forms" header is missing,
+// it's the rule of the germanic languages, e.g. english.
+var defaultGettextPluralRule gettextPluralRule = func(n int) int {
+	if n != 1 {
+		return 1
+	}
+
+	return 0
+}
+
+// parseGettextPluralForms parses the value of the "Plural-Forms" header,
+// e.g. "nplurals=2; plural=(n != 1);".
+func parseGettextPluralForms(header string) (gettextPluralRule, error) {
+	for _, field := range strings.Split(header, ";") {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, "plural") && !strings.HasPrefix(field, "nplurals") {
+			if idx := strings.IndexByte(field, '='); idx > 0 {
+				return parseGettextPluralRule(field[idx+1:])
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("po: Plural-Forms: missing plural expression: %s", header)
+}
+
+// parseGettextPluralRule compiles the C-like expression of a gettext plural rule,
+// e.g. "n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2".
+func parseGettextPluralRule(expr string) (gettextPluralRule, error) {
+	p := &pluralRuleParser{expr: expr}
+	rule, err := p.ternary()
+	if err != nil {
+		return nil, fmt.Errorf("po: Plural-Forms: %w", err)
+	}
+
+	if p.skipSpaces(); p.pos < len(p.expr) {
+		return nil, fmt.Errorf("po: Plural-Forms: unexpected %q at %d", p.expr[p.pos:], p.pos)
+	}
+
+	return rule, nil
+}
+
+type pluralRuleParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pluralRuleParser) skipSpaces() {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// consume reports whether the next token is "op" and skips it.
+func (p *pluralRuleParser) consume(op string) bool {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.expr[p.pos:], op) {
+		return false
+	}
+
+	// do not confuse "<" with "<=", "=" with "==" and "!" with "!=".
+	if len(op) == 1 && p.pos+1 < len(p.expr) && p.expr[p.pos+1] == '=' && strings.ContainsAny(op, "<>!=") {
+		return false
+	}
+
+	p.pos += len(op)
+	return true
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func (p *pluralRuleParser) ternary() (gettextPluralRule, error) {
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.consume("?") {
+		return cond, nil
+	}
+
+	left, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.consume(":") {
+		return nil, fmt.Errorf("expected ':' at %d", p.pos)
+	}
+
+	right, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n int) int {
+		if cond(n) != 0 {
+			return left(n)
+		}
+		return right(n)
+	}, nil
+}
+
+func (p *pluralRuleParser) or() (gettextPluralRule, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consume("||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(n int) int { return boolToInt(l(n) != 0 || right(n) != 0) }
+	}
+
+	return left, nil
+}
+
+func (p *pluralRuleParser) and() (gettextPluralRule, error) {
+	left, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consume("&&") {
+		right, err := p.binary(0)
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(n int) int { return boolToInt(l(n) != 0 && right(n) != 0) }
+	}
+
+	return left, nil
+}
+
+// pluralRuleOperators are the binary operators by precedence, lowest first.
+var pluralRuleOperators = [][]string{
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralRuleParser) binary(level int) (gettextPluralRule, error) {
+	if level == len(pluralRuleOperators) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := ""
+		for _, candidate := range pluralRuleOperators[level] {
+			if p.consume(candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		if op == "" {
+			return left, nil
+		}
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = pluralRuleBinary(op, left, right)
+	}
+}
+
+func pluralRuleBinary(op string, l, r gettextPluralRule) gettextPluralRule {
+	switch op {
+	case "==":
+		return func(n int) int { return boolToInt(l(n) == r(n)) }
+	case "!=":
+		return func(n int) int { return boolToInt(l(n) != r(n)) }
+	case "<=":
+		return func(n int) int { return boolToInt(l(n) <= r(n)) }
+	case ">=":
+		return func(n int) int { return boolToInt(l(n) >= r(n)) }
+	case "<":
+		return func(n int) int { return boolToInt(l(n) < r(n)) }
+	case ">":
+		return func(n int) int { return boolToInt(l(n) > r(n)) }
+	case "+":
+		return func(n int) int { return l(n) + r(n) }
+	case "-":
+		return func(n int) int { return l(n) - r(n) }
+	case "*":
+		return func(n int) int { return l(n) * r(n) }
+	case "/":
+		return func(n int) int {
+			if d := r(n); d != 0 {
+				return l(n) / d
+			}
+			return 0
+		}
+	default: // "%"
+		return func(n int) int {
+			if d := r(n); d != 0 {
+				return l(n) % d
+			}
+			return 0
+		}
+	}
+}
+
+func (p *pluralRuleParser) unary() (gettextPluralRule, error) {
+	if p.consume("!") {
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(n int) int { return boolToInt(operand(n) == 0) }, nil
+	}
+
+	if p.consume("(") {
+		rule, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.consume(")") {
+			return nil, fmt.Errorf("expected ')' at %d", p.pos)
+		}
+
+		return rule, nil
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.expr) && p.expr[p.pos] == 'n' {
+		p.pos++
+		return func(n int) int { return n }, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) && '0' <= p.expr[p.pos] && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+
+	if start == p.pos {
+		return nil, fmt.Errorf("unexpected end or character at %d", p.pos)
+	}
+
+	value, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		return nil, err
+	}
+
+	return func(int) int { return value }, nil
+}
+
+// appendLine appends the "line" to the "s" lines.
+func appendLine(s, line string) string {
+	if s == "" {
+		return line
+	}
+
+	return s + "\n" + line
+}
@@ -4,10 +4,15 @@ go 1.20
 
 require (
 	github.com/BurntSushi/toml v1.3.2
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/gertd/go-pluralize v0.2.1
 	golang.org/x/net v0.14.0
 	golang.org/x/text v0.12.0
 	gopkg.in/ini.v1 v1.67.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require github.com/stretchr/testify v1.8.2 // indirect
+require (
+	github.com/stretchr/testify v1.8.2 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
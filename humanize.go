@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"strings"
+	"unicode"
+)
+
+var _ MessageFunc = HumanizeKeyMessageFunc
+
+// HumanizeKeyMessageFunc is a `MessageFunc` which returns a human readable label
+// of the missing "key", e.g. for prototypes:
+//
+//	i18N.DefaultMessageFunc = i18n.HumanizeKeyMessageFunc
+//
+// It takes the last dotted segment of the key, splits its camelCase,
+// snake_case and kebab-case words and title-cases them,
+// e.g. "nav.more.what" -> "What", "user.firstName" -> "First Name"
+// and "errors.not_found" -> "Not Found".
+func HumanizeKeyMessageFunc(langInput, langMatched, key string, args ...interface{}) string {
+	if idx := strings.LastIndexByte(key, '.'); idx != -1 {
+		key = key[idx+1:]
+	}
+
+	return strings.Join(humanizeWords(key), " ")
+}
+
+// humanizeWords splits the "s" to its title-cased words, the acronyms are kept, e.g. "HTTPError" -> ["HTTP", "Error"].
+func humanizeWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			word[0] = unicode.ToUpper(word[0])
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	runes := []rune(s)
+	for idx, r := range runes {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+			// "firstName" or the "E" of "HTTPError".
+			if !unicode.IsUpper(prev) || (idx+1 < len(runes) && unicode.IsLower(runes[idx+1])) {
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
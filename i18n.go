@@ -2,11 +2,19 @@
 package i18n
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/kataras/i18n/internal"
 
@@ -47,10 +55,29 @@ type (
 	// Map is just an alias of the map[string]interface{} type.
 	Map = map[string]interface{}
 
+	// Delims are the template delimiters of a language, see the `LoaderConfig.Delims` field.
+	Delims = internal.Delims
+
 	// Locale is the type which the `Localizer.GetLocale` method returns.
 	// It serves the translations based on "key" or format. See its `GetMessage`.
 	Locale = internal.Locale
 
+	// Gender is a message argument which selects the gender form of a message,
+	// its "male", "female" or "other" sub-key, e.g. Tr("en-US", "HouseCount", i18n.Female, 2).
+	// It is removed from the arguments of the selected form
+	// and it is never confused with a plural count.
+	Gender = internal.Gender
+
+	// PluralCount is a message argument which selects the plural form of a message by its type
+	// instead of its position, e.g. Tr("en-US", "FreeDay", i18n.PluralCount(5), data),
+	// so it works without the `LoaderConfig.PositionalPluralCount` option.
+	// It is removed from the arguments, like the "count" of the `TrPlural` method.
+	PluralCount = internal.PluralCount
+
+	// MessageMeta is the metadata of a message, e.g. its translator comments,
+	// when the format of its locale file supports it, see `Locale.Meta`.
+	MessageMeta = internal.MessageMeta
+
 	// MessageFunc is the function type to modify the behavior when a key or language was not found.
 	// All language inputs fallback to the default locale if not matched.
 	// This is why this signature accepts both input and matched languages, so caller
@@ -68,6 +95,9 @@ type (
 	// Localizer is the interface which returned from a `Loader`.
 	// Types that implement this interface should be able to retrieve a `Locale`
 	// based on the language index.
+	//
+	// A Localizer which holds resources, e.g. goroutines, connections or file handles,
+	// may implement the `io.Closer` interface too, its Close method is called by `I18n.Close`.
 	Localizer interface {
 		// GetLocale should return a valid `Locale` based on the language index.
 		// It will always match the Loader.Matcher.Languages[index].
@@ -76,16 +106,79 @@ type (
 	}
 )
 
+var (
+	// ErrKeyNotFound is reported by `TrError` and `GetMessageError`
+	// when a translation key was not found, even on the default language.
+	ErrKeyNotFound = internal.ErrKeyNotFound
+	// ErrLanguageNotMatched is reported by `TrError` and `GetMessageError`
+	// when the input language does not match any of the registered languages.
+	ErrLanguageNotMatched = errors.New("language not matched")
+)
+
+// The genders, see `Gender`.
+const (
+	// OtherGender selects the "other" form, it is the default one.
+	OtherGender = internal.OtherGender
+	// Female selects the "female" form.
+	Female = internal.Female
+	// Male selects the "male" form.
+	Male = internal.Male
+)
+
+// The interpolation markers of a message value. A value which starts with a marker,
+// followed by a space or a new line, is interpolated by its mode instead of the detected one,
+// e.g. "#!text 100% {{literal}}". The marker takes precedence over the template delimiters.
+const (
+	// TemplateMarker marks a template message, even without the template delimiters.
+	TemplateMarker = internal.TemplateMarker
+	// PrintfMarker marks a printf-style message, its template delimiters
+	// and named placeholders are literal text.
+	PrintfMarker = internal.PrintfMarker
+	// TextMarker marks a static text message, its template delimiters,
+	// named placeholders, format verbs and variables are literal text.
+	TextMarker = internal.TextMarker
+)
+
+// ContextSeparator separates the key of a context-qualified message from its context,
+// e.g. the "Post@verb" and "Post@noun" keys, see `Locale.GetMessageContext`.
+const ContextSeparator = internal.ContextSeparator
+
 // I18n is the structure which keeps the i18n configuration and implements Localization and internationalization features.
 type I18n struct {
-	localizer Localizer
+	// localizer holds the current Localizer, it is swapped by `Reload`
+	// so the translations are read without a lock.
+	localizer atomic.Pointer[Localizer]
 	matcher   *Matcher
 
 	loader Loader
-	mu     sync.Mutex
+	// languages and defaultLang are set by the `WithLanguages` and `WithDefault` options.
+	languages   []string
+	defaultLang string
+
+	reloadMu sync.Mutex   // serializes the `Reload` calls.
+	mu       sync.RWMutex // protects the matcher, the default index, the fallbacks, the overlays and the match cache.
+	// defaultIndex is the language index of the default locale, which serves
+	// the requests of a not matched language and the keys which were not found.
+	defaultIndex int
+	// fallbacks holds the fallback languages of a language, see `SetFallback`.
+	fallbacks map[language.Tag][]language.Tag
+	// overlays holds the overridden messages of a language, by order, see `Overlay`.
+	overlays map[language.Tag][]*overlay
+	// messages holds the messages of the `SetMessages`, by order, they are stored on each load.
+	messages []langMessages
+	// matchCache holds the recent matched language inputs, see `DisableMatchCache`.
+	matchCache *matchCache
+	// loadedAt is the time of the last successful load, see `JSONHandler`.
+	loadedAt time.Time
+	// refResolver resolves the {{tr}} references of the template messages
+	// which are not found on their locale, like the keys of the `Tr`, see `refFallback`.
+	refResolver *internal.Resolver
+	// strictRefResolver does not resolve them, see `TrStrict`.
+	strictRefResolver *internal.Resolver
 
 	// If not nil, this request's context key can be used to identify the current language.
 	// The found language(in this case, by path or subdomain) will be also filled with the current language on `Router` method.
+	// If nil, the `WithLanguage` stores the language under a private key which is checked too.
 	ContextKey interface{}
 	// DefaultMessageFunc is the field which can be used
 	// to modify the behavior when a key or language was not found.
@@ -93,20 +186,90 @@ type I18n struct {
 	// This is why this one accepts both input and matched languages,
 	// so the caller can be more expressful knowing those.
 	//
+	// It is passed to the loaded locales too, e.g. for the `Locale.GetMessage`,
+	// call `Reload` if it is modified after `New` or use the `WithDefaultMessageFunc` option.
+	//
+	// Modifying it directly while the requests are served is not supported,
+	// use the `SetDefaultMessageFunc` method instead.
+	//
 	// Defaults to nil.
 	DefaultMessageFunc MessageFunc
+	// PluralFunc overrides the CLDR plural rules, e.g. for a constructed language.
+	// It returns the plural category of the "count", e.g. "one" or "other",
+	// which selects among the "zero", "one", "two", "few", "many" and "other"
+	// sub-keys of a plural message and is reported by the {{pluralCategory}} template function.
+	// The "other" sub-key is selected when no other one matches.
+	//
+	// It is passed to the loaded locales, as the `LoaderConfig.PluralFunc`,
+	// call `Reload` if it is modified after `New`.
+	//
+	// Defaults to nil, the CLDR plural rules of the language.
+	PluralFunc func(tag language.Tag, count interface{}) string
 	// ExtractFunc is the type signature for declaring custom logic
 	// to extract the language tag name.
 	ExtractFunc func(*http.Request) string
 	// If not empty, it is language identifier by url query.
+	// Its value can be a comma-separated list of languages by preference too,
+	// e.g. "?lang=el-GR,en-US", the first matched one wins.
 	URLParameter string
 	// If not empty, it is language identifier by cookie of this name.
+	// Its value can be a comma-separated list of languages by preference too.
 	Cookie string
+	// CookieOptions holds the attributes of the language cookie which
+	// the `Router` and `GetLocaleAndPersist` set.
+	CookieOptions CookieOptions
+	// If true then the `GetLocaleAndPersist` sets the `Cookie`
+	// to the language detected by the rest sources, e.g. the URL parameter.
+	SetCookieOnDetect bool
 	// If true then a subdomain can be a language identifier too.
 	Subdomain bool
+	// If true then the host of the requests is read from the X-Forwarded-Host
+	// or the Forwarded header, which are set by a reverse proxy, for the `Subdomain`
+	// detection and the domain of the language cookie.
+	// Enable it only behind a trusted proxy, the clients can send those headers too.
+	// Defaults to false.
+	TrustForwardedHeaders bool
+	// RedirectSkipper reports whether the `RedirectRouter` should not redirect
+	// a request to its language-prefixed path, e.g. for the assets.
+	RedirectSkipper func(*http.Request) bool
+	// If not empty, it is language identifier by request header of this name, e.g. "X-Locale".
+	// It is checked before the Accept-Language header.
+	Header string
+	// If true then the language inputs, e.g. the Accept-Language header values,
+	// are parsed and matched on each request.
+	// Defaults to false, a bounded cache of the recent matched inputs is used.
+	DisableMatchCache bool
+	// MinConfidence is the confidence threshold of the language matches,
+	// a language input matches a registered language only if
+	// the confidence of the match is higher than MinConfidence:
+	//  - language.No accepts any match, even the guesses by the script or the region,
+	//    e.g. "sr-Cyrl" matches the "sr-Latn", which may serve a text the user can not read.
+	//  - language.Low, the default, accepts the matches of the same base language and
+	//    a compatible script, e.g. "en-GB" matches the "en-US", and rejects the guesses,
+	//    e.g. "zh" does not match the "zh-Hant-TW".
+	//  - language.High accepts only the exact matches, e.g. "en" matches the "en-US"
+	//    but "en-GB" does not, the rest fallback to the default language.
+	//
+	// It is used by the language matching of the requests, `TryMatchString`, `SetDefault`,
+	// `SetFallback` and the loaders, call `Reload` if it is modified after `New`.
+	MinConfidence language.Confidence
+	// Sources declares the lookup order of the request's language,
+	// e.g. to let the Accept-Language header win over a stale cookie.
+	// Each source is used only if its field is set, e.g. `Cookie` for the `SourceCookie`.
+	//
+	// Defaults to nil, the `DefaultSources` order.
+	Sources []LanguageSource
 	// If true then it will return empty string when translation for a a specific language's key was not found.
 	// Defaults to false, fallback defaultLang:key will be used.
 	Strict bool
+	// OnLookup is an optional hook which is called on each translation lookup
+	// of the `Tr` and `GetMessage` methods (and their variants),
+	// e.g. to count the hits, fallbacks and misses per language.
+	// The "lang" is the input language and the "result" reports the language which served the message, if any.
+	// It should be set before the requests are served.
+	//
+	// Defaults to nil.
+	OnLookup func(lang, key string, result LookupResult)
 }
 
 // makeTags converts language codes to language Tags.
@@ -127,67 +290,553 @@ func makeTags(languages ...string) (tags []language.Tag) {
 // The "languages" input parameter is optional and if not empty then only these languages
 // will be used for translations and the rest (if any) will be skipped.
 // the first parameter of "loader" which lookups for translations inside files.
+// The first language is the default one.
+//
+// See `NewWithOptions` to configure the `I18n` before its first load,
+// e.g. to set the default language explicitly with the `WithDefault` option.
 func New(loader Loader, languages ...string) (*I18n, error) {
-	tags := makeTags(languages...)
+	i := newI18n(loader)
+	if err := i.load(languages); err != nil {
+		return nil, err
+	}
 
+	return i, nil
+}
+
+func newI18n(loader Loader) *I18n {
 	i := new(I18n)
 	i.loader = loader
+	i.MinConfidence = language.Low
+	i.refResolver = &internal.Resolver{Fallback: i.refFallback}
+	i.strictRefResolver = new(internal.Resolver)
+	return i
+}
+
+// load registers the "languages" and loads the locales for the first time.
+func (i *I18n) load(languages []string) error {
+	i.setMatcher(languages)
+	return i.Reload()
+}
+
+// setMatcher sets the matcher of the registered "languages", before the loading.
+func (i *I18n) setMatcher(languages []string) {
+	tags := makeTags(languages...)
+
 	i.matcher = &Matcher{
 		strict:             len(tags) > 0,
 		Languages:          tags,
 		matcher:            language.NewMatcher(tags),
 		defaultMessageFunc: i.DefaultMessageFunc,
+		pluralFunc:         i.PluralFunc,
+		minConfidence:      i.MinConfidence,
+		messages:           i.getMessages,
 	}
+}
 
-	if err := i.reload(); err != nil {
-		return nil, err
+// Reload loads the language files from the provided Loader again
+// and replaces the current translations, the `New` package-level function preloads those files already.
+// If the Loader fails then the previous translations are kept and the error is returned.
+//
+// It is safe to call `Tr`, `GetLocale` and `GetMessage` while reloading,
+// they are not blocked, they use the previous translations until the new ones are swapped in.
+func (i *I18n) Reload() error {
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	return i.reload()
+}
+
+// reload is the `Reload` without the reloadMu, the caller should hold it.
+func (i *I18n) reload() error {
+	// the loader runs against a copy of the matcher, without the lock,
+	// as it may add languages.
+	i.mu.RLock()
+	m := *i.matcher
+	m.Languages = append([]language.Tag(nil), i.matcher.Languages...)
+	m.minConfidence = i.MinConfidence
+	m.defaultMessageFunc = i.DefaultMessageFunc
+	m.pluralFunc = i.PluralFunc
+	m.defaultIndex = i.defaultIndex
+	messages := i.messages
+	i.mu.RUnlock()
+
+	localizer, err := i.loader(&m)
+	if err != nil {
+		return err
 	}
 
-	return i, nil
+	if len(messages) > 0 {
+		cat, ok := localizerCatalog(localizer)
+		if !ok {
+			return fmt.Errorf("i18n: set messages: unsupported localizer: %T", localizer)
+		}
+
+		// the new localizer is not used yet, so the messages are stored without a lock.
+		for _, lm := range messages {
+			if _, err = storeMessages(cat, &m, lm.tag, lm.messages); err != nil {
+				return err
+			}
+		}
+	}
+
+	if l, ok := localizer.(watchableLocalizer); ok {
+		if err = l.watch(i.getLocalizer(), i.Reload); err != nil {
+			return err
+		}
+	}
+
+	i.mu.Lock()
+	i.matcher = &m
+	i.localizer.Store(&localizer)
+	i.matchCache = newMatchCache(matchCacheSize) // the language indexes may have changed.
+	i.loadedAt = time.Now()
+	i.mu.Unlock()
+	return nil
+}
+
+// SetDefaultMessageFunc is package-level function which calls the `Default.SetDefaultMessageFunc` method.
+//
+// See `I18n#SetDefaultMessageFunc` method for more.
+func SetDefaultMessageFunc(fn MessageFunc) {
+	Default.SetDefaultMessageFunc(fn)
 }
 
-// reload loads the language files from the provided Loader,
-// the `New` package-level function preloads those files already.
-func (i *I18n) reload() error { // May be an exported function, if requested.
+// SetDefaultMessageFunc sets the `DefaultMessageFunc` field, safe for concurrent use
+// with the `Tr` and `GetMessage` methods, which use it on their next calls.
+// Modifying the field directly is not supported after the requests are served.
+//
+// The loaded locales, e.g. the `Locale.GetMessage`, use it after the next `Reload`.
+func (i *I18n) SetDefaultMessageFunc(fn MessageFunc) {
 	i.mu.Lock()
-	defer i.mu.Unlock()
+	i.DefaultMessageFunc = fn
+	if i.matcher != nil {
+		i.matcher.defaultMessageFunc = fn
+	}
+	i.mu.Unlock()
+}
 
-	localizer, err := i.loader(i.matcher)
-	if err != nil {
-		return err
+// getDefaultMessageFunc returns the `DefaultMessageFunc`, safe for concurrent use with `SetDefaultMessageFunc`.
+func (i *I18n) getDefaultMessageFunc() MessageFunc {
+	i.mu.RLock()
+	fn := i.DefaultMessageFunc
+	i.mu.RUnlock()
+	return fn
+}
+
+// getLocalizer returns the current localizer, safe for concurrent use with `Reload`.
+func (i *I18n) getLocalizer() Localizer {
+	if localizer := i.localizer.Load(); localizer != nil {
+		return *localizer
 	}
 
-	i.localizer = localizer
 	return nil
 }
 
-// SetDefault changes the default language.
-// Please avoid using this method; the default behavior will accept
-// the first language of the registered tags as the default one.
-func (i *I18n) SetDefault(langCode string) bool {
+// match calls the matcher's Match method, safe for concurrent use with `Reload`.
+// It reports no confidence if the languages are not loaded yet.
+func (i *I18n) match(t ...language.Tag) (language.Tag, int, language.Confidence) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.matcher == nil {
+		return language.Und, 0, language.No
+	}
+
+	return i.matcher.Match(t...)
+}
+
+// accepts reports whether a match of "conf" confidence is accepted, see `MinConfidence`.
+func (i *I18n) accepts(conf language.Confidence) bool {
+	return conf > i.MinConfidence
+}
+
+// getDefaultIndex returns the language index of the default locale, safe for concurrent use.
+func (i *I18n) getDefaultIndex() int {
+	i.mu.RLock()
+	index := i.defaultIndex
+	i.mu.RUnlock()
+	return index
+}
+
+// setDefaultIndex sets the default language to the registered one which matches the "langCode",
+// the order of the languages is kept. It reports false if the "langCode" is not matched.
+func (i *I18n) setDefaultIndex(langCode string) bool {
 	t, err := language.Parse(langCode)
 	if err != nil {
 		return false
 	}
 
-	if tag, index, conf := i.matcher.Match(t); conf > language.Low {
-		if l, ok := i.localizer.(interface {
-			SetDefault(int) bool
-		}); ok {
-			if l.SetDefault(index) {
-				tags := i.matcher.Languages
-				// set the order
-				tags[index] = tags[0]
-				tags[0] = tag
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, index, conf := i.matcher.Match(t); i.matcher.accepts(conf) {
+		i.defaultIndex = index
+		return true
+	}
+
+	return false
+}
+
+// getLocaleByIndex returns the locale of the language "index"
+// or nil if the locales are not loaded yet.
+func (i *I18n) getLocaleByIndex(index int) *Locale {
+	localizer := i.getLocalizer()
+	if localizer == nil {
+		return nil
+	}
+
+	return localizer.GetLocale(index)
+}
+
+// Close is package-level function which calls the `Default.Close` method.
+//
+// See `I18n#Close` method for more.
+func Close() error {
+	return Default.Close()
+}
+
+// Close stops any background work of the loader, e.g. the `Watch` one,
+// it calls the Close method of the loaded `Localizer`, if it implements the `io.Closer` interface.
+// It should be called on the shutdown of the server, the translations are still served after Close.
+func (i *I18n) Close() error {
+	if c, ok := i.getLocalizer().(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// SetFallback sets the fallback languages of the "langCode" language, by order.
+// When a key was not found on that language then the fallback ones are tried
+// before the default language, e.g. SetFallback("pt-BR", "pt-PT", "en-US").
+// The fallbacks of a fallback language are tried too, cycles are ignored.
+//
+// It returns false if "langCode" or any of the "fallbacks" do not match a registered language.
+func (i *I18n) SetFallback(langCode string, fallbacks ...string) bool {
+	tag, _, ok := i.TryMatchString(langCode)
+	if !ok {
+		return false
+	}
+
+	tags := make([]language.Tag, 0, len(fallbacks))
+	for _, fallback := range fallbacks {
+		fallbackTag, _, ok := i.TryMatchString(fallback)
+		if !ok {
+			return false
+		}
+
+		if fallbackTag != tag {
+			tags = append(tags, fallbackTag)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// copy on write, fallbackLocales reads the map without holding the lock.
+	m := make(map[language.Tag][]language.Tag, len(i.fallbacks)+1)
+	for k, v := range i.fallbacks {
+		m[k] = v
+	}
+	m[tag] = tags
+	i.fallbacks = m
+
+	return true
+}
+
+// refFallback returns the locales to look up a {{tr}} reference "key" which was not found on "loc",
+// the same ones as the `getMessage` does: the `SetFallback` chain of "loc" and the default one,
+// unless the Strict mode or a DefaultMessageFunc is set or the key should not fallback.
+func (i *I18n) refFallback(loc *Locale, key string) []*Locale {
+	if i.Strict || i.getDefaultMessageFunc() != nil || !loc.CanFallback(key) {
+		return nil
+	}
+
+	return i.fallbackLocales(i.getLocalizer(), loc)
+}
+
+// fallbackLocales returns the locales, by order, to try when a message of "loc" was not found:
+// the fallback languages of `SetFallback` and the default one.
+func (i *I18n) fallbackLocales(localizer Localizer, loc *Locale) []*Locale {
+	i.mu.RLock()
+	fallbacks := i.fallbacks
+	i.mu.RUnlock()
+
+	visited := map[int]struct{}{loc.Index(): {}}
+	var locales []*Locale
+
+	var walk func(tag language.Tag)
+	walk = func(tag language.Tag) {
+		for _, fallback := range fallbacks[tag] {
+			_, index, conf := i.match(fallback)
+			if !i.accepts(conf) {
+				continue
+			}
+
+			if _, ok := visited[index]; ok {
+				continue // break cycles.
+			}
+			visited[index] = struct{}{}
 
-				i.matcher.Languages = tags
-				i.matcher.matcher = language.NewMatcher(tags)
-				return true
+			if fallbackLoc := localizer.GetLocale(index); fallbackLoc != nil {
+				locales = append(locales, fallbackLoc)
+				walk(*fallbackLoc.Tag())
 			}
 		}
 	}
+	walk(*loc.Tag())
 
-	return false
+	defaultIndex := i.getDefaultIndex()
+	if _, ok := visited[defaultIndex]; !ok {
+		if defaultLoc := localizer.GetLocale(defaultIndex); defaultLoc != nil {
+			locales = append(locales, defaultLoc)
+		}
+	}
+
+	return locales
+}
+
+// Overlay is package-level function which calls the `Default.Overlay` method.
+//
+// See `I18n#Overlay` method for more.
+func Overlay(lang string, overrides map[string]string) (revert func()) {
+	return Default.Overlay(lang, overrides)
+}
+
+// Overlay overrides the messages of the "lang" language, e.g. to A/B test a text
+// without a redeploy, the loaded translations are not modified.
+// The "overrides" are plain text messages by key, they are rendered as they are.
+// Later overlays take precedence over earlier ones.
+//
+// It returns a function which removes the overlay, it is safe to call more than once.
+// It is safe for concurrent use.
+func (i *I18n) Overlay(lang string, overrides map[string]string) (revert func()) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return func() {}
+	}
+
+	if matched, _, ok := i.TryMatchString(lang); ok {
+		tag = matched
+	}
+
+	layer := &overlay{messages: make(map[string]string, len(overrides))}
+	for key, value := range overrides {
+		layer.messages[key] = value
+	}
+
+	i.setOverlays(tag, func(layers []*overlay) []*overlay {
+		return append(layers[:len(layers):len(layers)], layer)
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			i.setOverlays(tag, func(layers []*overlay) []*overlay {
+				filtered := make([]*overlay, 0, len(layers))
+				for _, l := range layers {
+					if l != layer {
+						filtered = append(filtered, l)
+					}
+				}
+				return filtered
+			})
+		})
+	}
+}
+
+// overlay is a layer of overridden messages, see `Overlay`.
+type overlay struct {
+	messages map[string]string
+}
+
+// setOverlays replaces the overlays of the "tag" language with the result of "update".
+func (i *I18n) setOverlays(tag language.Tag, update func([]*overlay) []*overlay) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// copy on write, getMessage reads the map without holding the lock.
+	m := make(map[language.Tag][]*overlay, len(i.overlays)+1)
+	for k, v := range i.overlays {
+		m[k] = v
+	}
+
+	if layers := update(m[tag]); len(layers) > 0 {
+		m[tag] = layers
+	} else {
+		delete(m, tag)
+	}
+
+	i.overlays = m
+}
+
+// overlayMessage returns the overridden message of the "key" on "loc", the latest overlay wins.
+func overlayMessage(overlays map[language.Tag][]*overlay, loc *Locale, key string) (string, bool) {
+	layers := overlays[*loc.Tag()]
+	for idx := len(layers) - 1; idx >= 0; idx-- {
+		if msg, ok := layers[idx].messages[key]; ok {
+			return msg, true
+		}
+	}
+
+	return "", false
+}
+
+// SetDefault changes the default language, which serves the requests of a not matched
+// language and the keys which were not found on the requested one.
+// The order of the languages is kept, so their indexes, e.g. a cached `Locale.Index`, stay valid.
+// It reports false if the "langCode" does not match a registered language.
+//
+// See the `WithDefault` option to set it before the first load.
+func (i *I18n) SetDefault(langCode string) bool {
+	return i.setDefaultIndex(langCode)
+}
+
+// SetMessage is package-level function which calls the `Default.SetMessage` method.
+//
+// See `I18n#SetMessage` method for more.
+func SetMessage(lang, key, value string) error {
+	return Default.SetMessage(lang, key, value)
+}
+
+// SetMessage registers a translation message of the "lang" language programmatically,
+// e.g. for tests or for plugins which contribute a few messages.
+//
+// See `SetMessages` method for more.
+func (i *I18n) SetMessage(lang, key, value string) error {
+	return i.SetMessages(lang, Map{key: value})
+}
+
+// SetMessages is package-level function which calls the `Default.SetMessages` method.
+//
+// See `I18n#SetMessages` method for more.
+func SetMessages(lang string, m Map) error {
+	return Default.SetMessages(lang, m)
+}
+
+// SetMessages registers the translation messages of the "lang" language programmatically,
+// their values are parsed like the values of the locale files, e.g. templates and plurals.
+// The language is added if the languages were not given to the `New` function.
+//
+// The messages are stored to a copy of the current translations, which is swapped in,
+// so it is safe for concurrent use with the `Tr` and `GetMessage`. They are stored
+// to the translations of each `Reload` too.
+// The localizers of the builtin loaders are supported.
+func (i *I18n) SetMessages(lang string, m Map) error {
+	t, err := language.Parse(lang)
+	if err != nil {
+		return err
+	}
+
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
+
+	localizer, ok := cloneLocalizer(i.getLocalizer())
+	if !ok {
+		return fmt.Errorf("i18n: set messages: unsupported localizer: %T", i.getLocalizer())
+	}
+	cat, _ := localizerCatalog(localizer)
+
+	i.mu.RLock()
+	matcher := *i.matcher
+	matcher.Languages = append([]language.Tag(nil), i.matcher.Languages...)
+	i.mu.RUnlock()
+
+	n := len(matcher.Languages)
+	if t, err = storeMessages(cat, &matcher, t, m); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	i.matcher = &matcher
+	i.messages = append(i.messages[:len(i.messages):len(i.messages)], langMessages{tag: t, messages: m})
+	i.localizer.Store(&localizer)
+	if len(matcher.Languages) > n { // added.
+		i.matchCache = newMatchCache(matchCacheSize)
+	}
+	i.mu.Unlock()
+
+	return nil
+}
+
+// getMessages returns the messages of the "tag" language of the `SetMessages`, by order.
+func (i *I18n) getMessages(tag language.Tag) []Map {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var messages []Map
+	for _, lm := range i.messages {
+		if lm.tag == tag {
+			messages = append(messages, lm.messages)
+		}
+	}
+
+	return messages
+}
+
+// langMessages are the messages of a language, see `SetMessages`.
+type langMessages struct {
+	tag      language.Tag
+	messages Map
+}
+
+// storeMessages stores the "messages" of the "tag" language to the "cat" catalog
+// of the "m" matcher, the language is added if the "m" is not strict.
+// It returns the matched language.
+func storeMessages(cat *internal.Catalog, m *Matcher, tag language.Tag, messages Map) (language.Tag, error) {
+	n := len(m.Languages)
+	matched, index, conf := m.MatchOrAdd(tag)
+	if !m.accepts(conf) {
+		return tag, fmt.Errorf("%w: %s", ErrLanguageNotMatched, tag)
+	}
+
+	if index >= n { // added.
+		cat.AddLocale(matched)
+	}
+
+	if err := cat.Store(index, messages); err != nil {
+		return matched, &LoadError{Lang: matched.String(), Err: err}
+	}
+
+	return matched, nil
+}
+
+// localizerCatalog returns the catalog of a localizer of the builtin loaders, see `SetMessages`.
+func localizerCatalog(localizer Localizer) (*internal.Catalog, bool) {
+	switch l := localizer.(type) {
+	case *internal.Catalog:
+		return l, true
+	case *chainLocalizer:
+		return l.Catalog, true
+	case *lazyLocalizer:
+		return l.Catalog, true
+	case *watchLocalizer:
+		return localizerCatalog(l.Localizer)
+	default:
+		return nil, false
+	}
+}
+
+// cloneLocalizer returns a copy of a localizer of the builtin loaders, see `Catalog.Clone`,
+// so more messages can be stored to it without affecting the readers of the "localizer".
+func cloneLocalizer(localizer Localizer) (Localizer, bool) {
+	switch l := localizer.(type) {
+	case *internal.Catalog:
+		return l.Clone(nil), true
+	case *chainLocalizer:
+		return &chainLocalizer{Catalog: l.Catalog.Clone(nil), localizers: l.localizers}, true
+	case *lazyLocalizer:
+		return l.clone(), true
+	case *watchLocalizer:
+		clone, ok := cloneLocalizer(l.Localizer)
+		if !ok {
+			return nil, false
+		}
+
+		// the clone keeps the file watcher of the I18n instance.
+		return &watchLocalizer{Localizer: clone, watcher: l.watcher, fileWatcher: l.fileWatcher, assetNames: l.assetNames}, true
+	default:
+		return nil, false
+	}
 }
 
 // Matcher implements the languae.Matcher.
@@ -197,8 +846,28 @@ type Matcher struct {
 	strict    bool
 	Languages []language.Tag
 	matcher   language.Matcher
-	// defaultMessageFunc passed by the i18n structure.
+	// defaultMessageFunc passed by the i18n structure, see `I18n.DefaultMessageFunc`.
+	// The loaders set it as the `LoaderConfig.DefaultMessageFunc` of their locales,
+	// if that is nil, which is used by the `Locale.GetMessage`
+	// and the {{tr}} template function on a missing key.
 	defaultMessageFunc MessageFunc
+	// defaultIndex passed by the i18n structure, the language index of its default locale,
+	// see `I18n.SetDefault`. E.g. the `Lazy` loaders load that locale on startup.
+	defaultIndex int
+	// pluralFunc passed by the i18n structure, see `I18n.PluralFunc`.
+	// The loaders set it as the `LoaderConfig.PluralFunc` of their locales, if that is nil.
+	pluralFunc func(tag language.Tag, count interface{}) string
+	// minConfidence passed by the i18n structure, see `I18n.MinConfidence`.
+	minConfidence language.Confidence
+	// messages passed by the i18n structure, it returns the messages of a language
+	// of the `I18n.SetMessages`, by order. E.g. the `Lazy` loaders store them
+	// over the translations of a language when they load it.
+	messages func(tag language.Tag) []Map
+}
+
+// accepts reports whether a match of "conf" confidence is accepted, see `I18n.MinConfidence`.
+func (m *Matcher) accepts(conf language.Confidence) bool {
+	return conf > m.minConfidence
 }
 
 var _ language.Matcher = (*Matcher)(nil)
@@ -206,8 +875,18 @@ var _ language.Matcher = (*Matcher)(nil)
 // Match returns the best match for any of the given tags, along with
 // a unique index associated with the returned tag and a confidence
 // score.
+//
+// A language which shares the base language of a registered one
+// matches that with a high confidence, e.g. "en-GB" matches the "en-US",
+// the returned tag is always the registered one.
 func (m *Matcher) Match(t ...language.Tag) (language.Tag, int, language.Confidence) {
-	return m.matcher.Match(t...)
+	tag, index, conf := m.matcher.Match(t...)
+	if index >= 0 && index < len(m.Languages) {
+		// drop the region extension of the loose matches, e.g. "en-US-u-rg-gbzzzz".
+		tag = m.Languages[index]
+	}
+
+	return tag, index, conf
 }
 
 // MatchOrAdd acts like Match but it checks and adds a language tag, if not found,
@@ -215,7 +894,7 @@ func (m *Matcher) Match(t ...language.Tag) (language.Tag, int, language.Confiden
 // and they should be dynamically added to the list.
 func (m *Matcher) MatchOrAdd(t language.Tag) (tag language.Tag, index int, conf language.Confidence) {
 	tag, index, conf = m.Match(t)
-	if conf <= language.Low && !m.strict {
+	if !m.accepts(conf) && !m.strict {
 		// not found, add it now.
 		m.Languages = append(m.Languages, t)
 		tag = t
@@ -230,10 +909,16 @@ func (m *Matcher) MatchOrAdd(t language.Tag) (tag language.Tag, index int, conf
 // ParseLanguageFiles returns a map of language indexes and
 // their associated files based on the "fileNames".
 func (m *Matcher) ParseLanguageFiles(fileNames []string) (map[int][]string, error) {
+	return m.parseLanguageFiles(fileNames, DefaultLoaderConfig), nil
+}
+
+// parseLanguageFiles same as `ParseLanguageFiles` but it respects
+// the loader "options", e.g. the `LoaderConfig.LangFromDir`.
+func (m *Matcher) parseLanguageFiles(fileNames []string, options LoaderConfig) map[int][]string {
 	languageFiles := make(map[int][]string)
 
 	for _, fileName := range fileNames {
-		index := parsePath(m, fileName)
+		index := parsePath(m, fileName, options)
 		if index == -1 {
 			continue
 		}
@@ -241,12 +926,37 @@ func (m *Matcher) ParseLanguageFiles(fileNames []string) (map[int][]string, erro
 		languageFiles[index] = append(languageFiles[index], fileName)
 	}
 
-	return languageFiles, nil
+	return languageFiles
 }
 
-func parsePath(m *Matcher, path string) int {
-	if t, ok := parseLanguage(path); ok {
-		if _, index, conf := m.MatchOrAdd(t); conf > language.Low {
+func parsePath(m *Matcher, path string, options LoaderConfig) int {
+	if options.OnlyRegisteredLanguages && m.strict {
+		// try all language-like words of the path, e.g. "no" of "el-GR/no.yml"
+		// is not a registered language, so the "el-GR" is used.
+		candidates := parseLanguages(path)
+		if options.LangFromDir {
+			candidates = candidates[:0]
+			if t, ok := parseDirLanguage(path); ok {
+				candidates = append(candidates, t)
+			}
+		}
+
+		for _, t := range candidates {
+			if _, index, conf := m.Match(t); m.accepts(conf) {
+				return index
+			}
+		}
+
+		return -1
+	}
+
+	parse := parseLanguage
+	if options.LangFromDir {
+		parse = parseDirLanguage
+	}
+
+	if t, ok := parse(path); ok {
+		if _, index, conf := m.MatchOrAdd(t); m.accepts(conf) {
 			return index
 		}
 	}
@@ -256,7 +966,7 @@ func parsePath(m *Matcher, path string) int {
 
 func parseLanguageName(m *Matcher, name string) int {
 	if t, err := language.Parse(name); err == nil {
-		if _, index, conf := m.MatchOrAdd(t); conf > language.Low {
+		if _, index, conf := m.MatchOrAdd(t); m.accepts(conf) {
 			return index
 		}
 	}
@@ -272,6 +982,27 @@ func reverseStrings(s []string) []string {
 }
 
 func parseLanguage(path string) (language.Tag, bool) {
+	if tags := parseLanguages(path); len(tags) > 0 {
+		return tags[0], true
+	}
+
+	return language.Und, false
+}
+
+// parseLanguages returns the language tags of the "path" words,
+// the last one first, e.g. the file name's language before the directory's one.
+func parseLanguages(path string) []language.Tag {
+	if lang, ok := appleLanguage(path); ok {
+		// The Apple localization directory, e.g. "el.lproj", is the only language source,
+		// the "Base.lproj" has none.
+		if t, err := language.Parse(lang); err == nil {
+			return []language.Tag{t}
+		}
+
+		return nil
+	}
+
+	android := strings.HasSuffix(path, ".xml")
 	if idx := strings.LastIndexByte(path, '.'); idx > 0 {
 		path = path[0:idx]
 	}
@@ -284,29 +1015,102 @@ func parseLanguage(path string) (language.Tag, bool) {
 
 	names = reverseStrings(names) // see https://github.com/kataras/i18n/issues/1
 
+	var tags []language.Tag
 	for _, s := range names {
+		if android {
+			if s == "values" {
+				// The Android default resources directory,
+				// do not read its parents, e.g. the "res".
+				break
+			}
+
+			if lang, ok := androidLanguage(s); ok {
+				s = lang
+			}
+		}
+
 		t, err := language.Parse(s)
 		if err != nil {
 			continue
 		}
 
-		return t, true
+		tags = append(tags, t)
 	}
 
-	return language.Und, false
+	return tags
 }
 
-// TryMatchString will try to match the "s" with a registered language tag.
-// It returns -1 as the language index and false if not found.
-func (i *I18n) TryMatchString(s string) (language.Tag, int, bool) {
-	if tag, err := language.Parse(s); err == nil {
-		if tag, index, conf := i.matcher.Match(tag); conf > language.Low {
-			return tag, index, true
-		}
+// parseDirLanguage returns the language of the immediate parent directory of the "path",
+// e.g. "en-US" for the "./locales/en-US/buttons.en.yml", see `LoaderConfig.LangFromDir`.
+func parseDirLanguage(path string) (language.Tag, bool) {
+	idx := strings.LastIndexFunc(path, isPathSeparator)
+	if idx <= 0 {
+		return language.Und, false
 	}
 
-	return language.Und, -1, false
-}
+	dir := path[:idx]
+	if idx = strings.LastIndexFunc(dir, isPathSeparator); idx >= 0 {
+		dir = dir[idx+1:]
+	}
+
+	t, err := language.Parse(dir)
+	if err != nil {
+		return language.Und, false
+	}
+
+	return t, true
+}
+
+func isPathSeparator(r rune) bool {
+	return r == os.PathSeparator || r == '/'
+}
+
+// TryMatchString will try to match the "s" with a registered language tag.
+// Both '-' and '_' separators are accepted, e.g. "zh-cn" and "zh_cn" match the "zh-CN".
+// It returns -1 as the language index and false if not found.
+//
+// See `TryMatchStringConfidence` too.
+func (i *I18n) TryMatchString(s string) (language.Tag, int, bool) {
+	tag, index, conf := i.TryMatchStringConfidence(s)
+	if i.accepts(conf) {
+		return tag, index, true
+	}
+
+	return language.Und, -1, false
+}
+
+// TryMatchStringConfidence same as `TryMatchString` but it returns the confidence
+// of the match instead of a boolean, e.g. to separate an exact match
+// from a loose one, like "es-419" matched to "es".
+// It returns -1 as the language index and `language.No` if "s" is not a valid language code.
+func (i *I18n) TryMatchStringConfidence(s string) (language.Tag, int, language.Confidence) {
+	return i.cachedMatch(matchCacheKey{input: s}, func() (language.Tag, int, language.Confidence) {
+		tag, err := language.Parse(s)
+		if err != nil {
+			return language.Und, -1, language.No
+		}
+
+		return i.match(tag)
+	})
+}
+
+// MatchAcceptLanguage matches the "header" value of an Accept-Language header,
+// e.g. "fr;q=0.2, de;q=0.9", with a registered language tag.
+// The languages are tried by their quality value, higher first, so "de" wins on that example.
+// It returns the matched language tag, its index and the confidence of the match,
+// so the caller can decide whether to trust it; the confidence is `language.No`
+// and the index -1 if the header is malformed.
+func (i *I18n) MatchAcceptLanguage(header string) (language.Tag, int, language.Confidence) {
+	return i.cachedMatch(matchCacheKey{input: header, acceptLanguage: true}, func() (language.Tag, int, language.Confidence) {
+		// the tags are sorted by their quality value and the q=0 ones are excluded.
+		desired, _, err := language.ParseAcceptLanguage(header)
+		if err != nil || len(desired) == 0 {
+			return language.Und, -1, language.No
+		}
+
+		return i.match(desired...)
+	})
+}
 
 // Tr is package-level function which calls the `Default.Tr` method.
 //
@@ -320,32 +1124,400 @@ func Tr(lang, format string, args ...interface{}) string {
 //
 // It returns an empty string if "lang" not matched, unless DefaultMessageFunc.
 // It returns the default language's translation if "key" not matched, unless DefaultMessageFunc.
-func (i *I18n) Tr(lang, format string, args ...interface{}) (msg string) {
+func (i *I18n) Tr(lang, format string, args ...interface{}) string {
+	msg, _ := i.TrError(lang, format, args...)
+	return msg
+}
+
+// TrContext is package-level function which calls the `Default.TrContext` method.
+//
+// See `I18n#TrContext` method for more.
+func TrContext(ctx context.Context, format string, args ...interface{}) string {
+	return Default.TrContext(ctx, format, args...)
+}
+
+// TrContext same as `Tr` but it reads the language code from the "ctx" value of the `ContextKey`,
+// e.g. on background jobs where there is no *http.Request.
+// It translates on the default language if the language value was not found.
+func (i *I18n) TrContext(ctx context.Context, format string, args ...interface{}) string {
+	lang, _ := ctx.Value(i.contextKey()).(string)
+
+	return i.Tr(lang, format, args...)
+}
+
+// TrError is package-level function which calls the `Default.TrError` method.
+//
+// See `I18n#TrError` method for more.
+func TrError(lang, format string, args ...interface{}) (string, error) {
+	return Default.TrError(lang, format, args...)
+}
+
+// TrError same as `Tr` but it returns an error too.
+// The error wraps the ErrLanguageNotMatched if the "lang" did not match a registered language,
+// the ErrKeyNotFound if the "key" was not found, even on the default language,
+// or the template's render error. Use the `errors.Is` to check against them.
+//
+// The returned message is always the same as `Tr` returns.
+func (i *I18n) TrError(lang, format string, args ...interface{}) (string, error) {
+	msg, _, err := i.tr(lang, format, args, func(loc *Locale, r *internal.Resolver) (string, error) {
+		return loc.ResolveMessageError(r, format, args...)
+	})
+	return msg, err
+}
+
+// TrStrict is package-level function which calls the `Default.TrStrict` method.
+//
+// See `I18n#TrStrict` method for more.
+func TrStrict(lang, format string, args ...interface{}) string {
+	return Default.TrStrict(lang, format, args...)
+}
+
+// TrStrict same as `Tr` but it never fallbacks to the default (or the `SetFallback`) language,
+// regardless of the `Strict` field, e.g. to render an "[untranslated]" placeholder for a specific widget.
+// It returns an empty string, unless DefaultMessageFunc, if the "key" was not found on "lang"
+// or "lang" was not matched.
+func (i *I18n) TrStrict(lang, format string, args ...interface{}) string {
+	_, index, ok := i.TryMatchString(lang)
+	msg, _, _ := i.trIndex(index, ok, true, func() string { return lang }, format, args, func(loc *Locale, r *internal.Resolver) (string, error) {
+		return loc.ResolveMessageError(r, format, args...)
+	})
+	return msg
+}
+
+// TrPlural is package-level function which calls the `Default.TrPlural` method.
+//
+// See `I18n#TrPlural` method for more.
+func TrPlural(lang, key string, count int, args ...interface{}) string {
+	return Default.TrPlural(lang, key, count, args...)
+}
+
+// TrPlural same as `Tr` but the plural form of the message is selected by the "count",
+// instead of the first of the "args", so a template data of integer fields is not confused with it.
+//
+// The plural forms are the sub-keys of the message: the CLDR plural categories
+// "zero", "one", "two", "few", "many" and "other", selected by the plural rules of the language,
+// e.g. "many" for 5 in "ru", and the "=5", "<5" or ">5" ones.
+// See `LoaderConfig.PluralFormDecoder` for customizations.
+// The "count" is the first argument of the printf-style plural forms, e.g. "%d files",
+// and the first of the "args" is the data of the template ones, or the "count" if "args" is empty.
+func (i *I18n) TrPlural(lang, key string, count int, args ...interface{}) string {
+	msg, _, _ := i.tr(lang, key, args, func(loc *Locale, r *internal.Resolver) (string, error) {
+		return loc.ResolvePluralMessageError(r, key, count, args...)
+	})
+	return msg
+}
+
+// TrVerbose is package-level function which calls the `Default.TrVerbose` method.
+//
+// See `I18n#TrVerbose` method for more.
+func TrVerbose(lang, format string, args ...interface{}) (string, string, bool) {
+	return Default.TrVerbose(lang, format, args...)
+}
+
+// TrVerbose same as `Tr` but it reports the language which served the message too,
+// and whether that is a fallback one, e.g. the default language because the
+// "key" was not found on "lang" or "lang" not matched, to debug the fallback behavior.
+// The served language is empty if the message was not found on any language.
+func (i *I18n) TrVerbose(lang, format string, args ...interface{}) (msg string, servedLang string, fromFallback bool) {
+	var served *Locale
+	msg, served, _ = i.tr(lang, format, args, func(loc *Locale, r *internal.Resolver) (string, error) {
+		return loc.ResolveMessageError(r, format, args...)
+	})
+
+	if served != nil {
+		servedLang = served.Language()
+		_, index, ok := i.TryMatchString(lang)
+		fromFallback = !ok || served.Index() != index
+	}
+
+	return
+}
+
+// TrTag is package-level function which calls the `Default.TrTag` method.
+//
+// See `I18n#TrTag` method for more.
+func TrTag(tag language.Tag, format string, args ...interface{}) string {
+	return Default.TrTag(tag, format, args...)
+}
+
+// TrTag same as `Tr` but it accepts a language tag, e.g. an already matched one,
+// so it is not parsed from a language code again.
+func (i *I18n) TrTag(tag language.Tag, format string, args ...interface{}) string {
+	index, ok := i.matchTag(tag)
+	msg, _, _ := i.trIndex(index, ok, false, tag.String, format, args, func(loc *Locale, r *internal.Resolver) (string, error) {
+		return loc.ResolveMessageError(r, format, args...)
+	})
+	return msg
+}
+
+// GetLocaleByTag is package-level function which calls the `Default.GetLocaleByTag` method.
+//
+// See `I18n#GetLocaleByTag` method for more.
+func GetLocaleByTag(tag language.Tag) *Locale {
+	return Default.GetLocaleByTag(tag)
+}
+
+// GetLocaleByTag returns the locale of the language "tag", e.g. an already matched one,
+// so it is not parsed from a language code again.
+// It returns nil if "tag" not matched.
+func (i *I18n) GetLocaleByTag(tag language.Tag) *Locale {
+	index, ok := i.matchTag(tag)
+	if !ok {
+		return nil
+	}
+
+	return i.getLocaleByIndex(index)
+}
+
+// LocaleAt is package-level function which calls the `Default.LocaleAt` method.
+//
+// See `I18n#LocaleAt` method for more.
+func LocaleAt(index int) *Locale {
+	return Default.LocaleAt(index)
+}
+
+// LocaleAt returns the locale of the registered language "index", starting from zero,
+// e.g. a cached `Locale.Index`, or to iterate all locales until it returns nil.
+// It returns nil if the "index" is out of range or the locales are not loaded yet.
+func (i *I18n) LocaleAt(index int) *Locale {
+	if index < 0 {
+		return nil
+	}
+
+	loc := i.getLocaleByIndex(index)
+	if loc == nil || loc.Index() != index {
+		return nil
+	}
+
+	return loc
+}
+
+// AllLocales is package-level function which calls the `Default.AllLocales` method.
+//
+// See `I18n#AllLocales` method for more.
+func AllLocales() []*Locale {
+	return Default.AllLocales()
+}
+
+// AllLocales returns the locales of the registered languages, by their index,
+// e.g. to build a language picker or a sitemap of the localized pages.
+// It works with any `Localizer`, it calls its `GetLocale` for each language index.
+// It returns nil if the locales are not loaded yet.
+func (i *I18n) AllLocales() []*Locale {
+	i.mu.RLock()
+	n := 0
+	if i.matcher != nil {
+		n = len(i.matcher.Languages)
+	}
+	i.mu.RUnlock()
+
+	var locales []*Locale
+	for index := 0; index < n; index++ {
+		if loc := i.LocaleAt(index); loc != nil {
+			locales = append(locales, loc)
+		}
+	}
+
+	return locales
+}
+
+// DefaultLocale is package-level function which calls the `Default.DefaultLocale` method.
+//
+// See `I18n#DefaultLocale` method for more.
+func DefaultLocale() *Locale {
+	return Default.DefaultLocale()
+}
+
+// DefaultLocale returns the locale of the default language, which is not always the index zero one,
+// see `SetDefault`. It returns nil if the locales are not loaded yet.
+func (i *I18n) DefaultLocale() *Locale {
+	return i.LocaleAt(i.getDefaultIndex())
+}
+
+// matchTag returns the index of the registered language which matches the "tag".
+// A registered tag is found without the matcher.
+func (i *I18n) matchTag(tag language.Tag) (int, bool) {
+	i.mu.RLock()
+	if i.matcher != nil {
+		for index, t := range i.matcher.Languages {
+			if t == tag {
+				i.mu.RUnlock()
+				return index, true
+			}
+		}
+	}
+	i.mu.RUnlock()
+
+	_, index, conf := i.match(tag)
+	if !i.accepts(conf) {
+		return -1, false
+	}
+
+	return index, true
+}
+
+// tr completes the `Tr` methods, "get" should return the message of the given locale.
+// It returns the locale which served the message too, if any.
+func (i *I18n) tr(lang, format string, args []interface{}, get func(*Locale, *internal.Resolver) (string, error)) (msg string, served *Locale, err error) {
 	_, index, ok := i.TryMatchString(lang)
+	return i.trIndex(index, ok, false, func() string { return lang }, format, args, get)
+}
+
+// trIndex completes the `tr`, `TrTag` and `TrStrict` methods for the matched language "index",
+// "lang" returns the language input, e.g. for the DefaultMessageFunc.
+// If "strict" is true then it never fallbacks to the default language, even if "lang" was not matched.
+func (i *I18n) trIndex(index int, ok, strict bool, lang func() string, format string, args []interface{}, get func(*Locale, *internal.Resolver) (string, error)) (msg string, served *Locale, err error) {
 	if !ok {
-		index = 0
+		index = i.getDefaultIndex()
+		err = fmt.Errorf("%w: %s", ErrLanguageNotMatched, lang())
 	}
 
 	langMatched := ""
 
-	loc := i.localizer.GetLocale(index)
+	var loc *Locale
+	if ok || !strict {
+		loc = i.getLocaleByIndex(index)
+	}
+
 	if loc != nil {
 		langMatched = loc.Language()
 
-		msg = loc.GetMessage(format, args...)
-		if msg == "" && i.DefaultMessageFunc == nil && !i.Strict && index > 0 {
-			// it's not the default/fallback language and not message found for that lang:key.
-			msg = i.localizer.GetLocale(0).GetMessage(format, args...)
+		var msgErr error
+		msg, served, msgErr = i.getMessage(i.getLocalizer(), loc, format, strict, get)
+		if err == nil {
+			err = msgErr
 		}
+	} else if err == nil {
+		err = fmt.Errorf("%w: %s", ErrLanguageNotMatched, lang())
 	}
 
-	if msg == "" && i.DefaultMessageFunc != nil {
-		msg = i.DefaultMessageFunc(lang, langMatched, format, args...)
+	if i.OnLookup != nil {
+		i.OnLookup(lang(), format, newLookupResult(loc, served, ok))
+	}
+
+	if fn := i.getDefaultMessageFunc(); msg == "" && fn != nil {
+		msg = fn(lang(), langMatched, format, args...)
 	}
 
 	return
 }
 
+// getMessage returns the translated message of "loc" and fallbacks to the fallback languages
+// and the default one if not found, unless "strict", DefaultMessageFunc, Strict or the key should not fallback.
+// It returns the locale which served the message too, nil if not found.
+func (i *I18n) getMessage(localizer Localizer, loc *Locale, key string, strict bool, get func(*Locale, *internal.Resolver) (string, error)) (string, *Locale, error) {
+	i.mu.RLock()
+	overlays := i.overlays
+	hasDefaultMessageFunc := i.DefaultMessageFunc != nil
+	i.mu.RUnlock()
+
+	if getLoaded := get; len(overlays) > 0 {
+		get = func(loc *Locale, r *internal.Resolver) (string, error) {
+			if msg, ok := overlayMessage(overlays, loc, key); ok {
+				return msg, nil
+			}
+
+			return getLoaded(loc, r)
+		}
+	}
+
+	resolver := i.refResolver
+	if strict {
+		resolver = i.strictRefResolver
+	}
+
+	served := loc
+	msg, err := get(loc, resolver)
+	if msg == "" && isNotFound(err) && !strict && !hasDefaultMessageFunc && !i.Strict && loc.CanFallback(key) {
+		// no message found for that lang:key.
+		for _, fallbackLoc := range i.fallbackLocales(localizer, loc) {
+			served = fallbackLoc
+			if msg, err = get(fallbackLoc, resolver); msg != "" || !isNotFound(err) {
+				break
+			}
+		}
+	}
+
+	if err != nil && errors.Is(err, ErrKeyNotFound) {
+		served = nil
+	}
+
+	if !isNotFound(err) {
+		// render error.
+		msg = err.Error()
+	}
+
+	return msg, served, err
+}
+
+func isNotFound(err error) bool {
+	return err == nil || errors.Is(err, ErrKeyNotFound)
+}
+
+// Exists is package-level function which calls the `Default.Exists` method.
+//
+// See `I18n#Exists` method for more.
+func Exists(lang, key string) bool {
+	return Default.Exists(lang, key)
+}
+
+// Exists reports whether a translation for the "key" exists on the "lang" language.
+// Unlike `Tr`, it does not fallback to the default language.
+func (i *I18n) Exists(lang, key string) bool {
+	_, index, ok := i.TryMatchString(lang)
+	if !ok {
+		return false
+	}
+
+	loc := i.getLocaleByIndex(index)
+	return loc != nil && loc.Index() == index && loc.Exists(key)
+}
+
+// Keys is package-level function which calls the `Default.Keys` method.
+//
+// See `I18n#Keys` method for more.
+func Keys(lang string) []string {
+	return Default.Keys(lang)
+}
+
+// Keys returns the sorted translation keys of the "lang" language.
+// Nested keys are separated by dot, e.g. "nav.more.what".
+// It returns nil if "lang" not matched.
+func (i *I18n) Keys(lang string) []string {
+	_, index, ok := i.TryMatchString(lang)
+	if !ok {
+		return nil
+	}
+
+	loc := i.getLocaleByIndex(index)
+	if loc == nil || loc.Index() != index {
+		return nil
+	}
+
+	return loc.Keys()
+}
+
+// MissingKeys is package-level function which calls the `Default.MissingKeys` method.
+//
+// See `I18n#MissingKeys` method for more.
+func MissingKeys(reference, target string) []string {
+	return Default.MissingKeys(reference, target)
+}
+
+// MissingKeys returns the sorted keys which exist on the "reference" language
+// but not on the "target" one, e.g. to report incomplete translations.
+// It returns nil if "reference" not matched.
+func (i *I18n) MissingKeys(reference, target string) []string {
+	var missing []string
+	for _, key := range i.Keys(reference) {
+		if !i.Exists(target, key) {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
 const acceptLanguageHeaderKey = "Accept-Language"
 
 // GetLocale is package-level function which calls the `Default.GetLocale` method.
@@ -358,73 +1530,270 @@ func GetLocale(r *http.Request) *Locale {
 // GetLocale returns the found locale of a request.
 // It will return the first registered language if nothing else matched.
 func (i *I18n) GetLocale(r *http.Request) *Locale {
-	var (
-		index int
-		ok    bool
-	)
+	loc, _, _ := i.getLocale(r)
+	return loc
+}
 
-	if i.ContextKey != nil {
-		if v := r.Context().Value(i.ContextKey); v != nil {
-			if s, isString := v.(string); isString {
-				if v == "default" {
-					index = 0 // no need to call `TryMatchString` and spend time.
-				} else {
-					_, index, _ = i.TryMatchString(s)
+// GetLocaleAndPersist is package-level function which calls the `Default.GetLocaleAndPersist` method.
+//
+// See `I18n#GetLocaleAndPersist` method for more.
+func GetLocaleAndPersist(w http.ResponseWriter, r *http.Request) *Locale {
+	return Default.GetLocaleAndPersist(w, r)
+}
+
+// GetLocaleAndPersist same as `GetLocale` but, if the `SetCookieOnDetect` is true,
+// it also sets the `Cookie` to the detected language when it was detected
+// from another source than the cookie itself, e.g. by the URL parameter,
+// so the next requests without that parameter keep the same language.
+// The language forced by the `WithLanguage` is not persisted.
+func (i *I18n) GetLocaleAndPersist(w http.ResponseWriter, r *http.Request) *Locale {
+	loc, _, source := i.getLocale(r)
+	if loc == nil || !i.SetCookieOnDetect || i.Cookie == "" {
+		return loc
+	}
+
+	switch source {
+	case 0, SourceContext, SourceCookie:
+		return loc
+	}
+
+	lang := loc.Language()
+	if cookie, err := r.Cookie(i.Cookie); err == nil && cookie.Value == lang {
+		return loc
+	}
+
+	i.setCookie(w, r, lang)
+	return loc
+}
+
+// LanguageSource is a source of the request's language, see the `I18n.Sources` field.
+type LanguageSource uint8
+
+// The available language sources.
+const (
+	// SourceContext is the request context value of the `I18n.ContextKey`, see `WithLanguage` too.
+	// Unlike the rest sources, a found value is used even if it is not matched.
+	SourceContext LanguageSource = iota + 1
+	// SourceExtractFunc is the result of the `I18n.ExtractFunc`.
+	SourceExtractFunc
+	// SourceURLParameter is the url query parameter of the `I18n.URLParameter`.
+	SourceURLParameter
+	// SourceCookie is the cookie of the `I18n.Cookie` name.
+	SourceCookie
+	// SourceSubdomain is the subdomain, when `I18n.Subdomain` is true.
+	SourceSubdomain
+	// SourceHeader is the request header of the `I18n.Header` name.
+	SourceHeader
+	// SourceAcceptLanguage is the Accept-Language request header.
+	SourceAcceptLanguage
+)
+
+// DefaultSources is the default lookup order of the request's language.
+var DefaultSources = []LanguageSource{
+	SourceContext,
+	SourceExtractFunc,
+	SourceURLParameter,
+	SourceCookie,
+	SourceSubdomain,
+	SourceHeader,
+	SourceAcceptLanguage,
+}
+
+// getLocale returns the request's locale, the best-known input language,
+// the value which matched or, if none, the first one found on the request,
+// and the source which matched, zero if none.
+func (i *I18n) getLocale(r *http.Request) (*Locale, string, LanguageSource) {
+	sources := i.Sources
+	if len(sources) == 0 {
+		sources = DefaultSources
+	}
+
+	langInput := ""
+
+	for _, source := range sources {
+		switch source {
+		case SourceContext:
+			if loc, ok := r.Context().Value(localeContextKey{}).(*Locale); ok && loc != nil {
+				return loc, loc.Language(), source // resolved by the `Middleware`.
+			}
+
+			if s, ok := r.Context().Value(i.contextKey()).(string); ok {
+				index := i.getDefaultIndex() // "default", no need to call `TryMatchString` and spend time.
+				if s != "default" {
+					if _, idx, ok := i.TryMatchString(s); ok {
+						index = idx
+					}
+				}
+
+				return i.getLocaleByIndex(index), s, source
+			}
+		case SourceAcceptLanguage:
+			if v := r.Header.Get(acceptLanguageHeaderKey); v != "" {
+				if _, index, conf := i.MatchAcceptLanguage(v); i.accepts(conf) {
+					return i.getLocaleByIndex(index), v, source
 				}
 
-				locale := i.localizer.GetLocale(index)
-				if locale == nil {
-					return nil
+				if langInput == "" {
+					langInput = v
+				}
+			}
+		case SourceURLParameter, SourceCookie:
+			// a single language or a list of them by preference, e.g. "el-GR,en-US".
+			if v := i.getLanguageInput(r, source); v != "" {
+				if _, index, ok := i.tryMatchList(v); ok {
+					return i.getLocaleByIndex(index), v, source
 				}
 
-				return locale
+				if langInput == "" {
+					langInput = v
+				}
+			}
+		default:
+			if v := i.getLanguageInput(r, source); v != "" {
+				if _, index, ok := i.TryMatchString(v); ok {
+					return i.getLocaleByIndex(index), v, source
+				}
+
+				if langInput == "" {
+					langInput = v
+				}
 			}
 		}
 	}
 
-	if !ok && i.ExtractFunc != nil {
-		if v := i.ExtractFunc(r); v != "" {
-			_, index, ok = i.TryMatchString(v)
-		}
+	// defaults to the default language.
+	return i.getLocaleByIndex(i.getDefaultIndex()), langInput, 0
+}
+
+// tryMatchList same as `TryMatchString` but "s" can be a comma-separated list
+// of languages by preference too, e.g. "el-GR,en-US", which is matched like
+// an Accept-Language header value.
+func (i *I18n) tryMatchList(s string) (language.Tag, int, bool) {
+	if strings.IndexByte(s, ',') == -1 {
+		return i.TryMatchString(s)
 	}
 
-	if !ok && i.URLParameter != "" {
-		if v := r.URL.Query().Get(i.URLParameter); v != "" {
-			_, index, ok = i.TryMatchString(v)
-		}
+	tag, index, conf := i.MatchAcceptLanguage(s)
+	if !i.accepts(conf) {
+		return language.Und, -1, false
 	}
 
-	if !ok && i.Cookie != "" {
-		cookie, err := r.Cookie(i.Cookie)
-		if err == nil {
-			_, index, ok = i.TryMatchString(cookie.Value) // url.QueryUnescape(cookie.Value)
+	return tag, index, true
+}
+
+// getLanguageInput returns the language code of the request's "source",
+// if it is enabled, or empty string.
+func (i *I18n) getLanguageInput(r *http.Request, source LanguageSource) string {
+	switch source {
+	case SourceExtractFunc:
+		if i.ExtractFunc != nil {
+			return i.ExtractFunc(r)
+		}
+	case SourceURLParameter:
+		if i.URLParameter != "" {
+			return r.URL.Query().Get(i.URLParameter)
+		}
+	case SourceCookie:
+		if i.Cookie != "" {
+			if cookie, err := r.Cookie(i.Cookie); err == nil {
+				return cookie.Value // url.QueryUnescape(cookie.Value)
+			}
+		}
+	case SourceSubdomain:
+		if i.Subdomain {
+			subdomain, _ := i.getSubdomain(r)
+			return subdomain
+		}
+	case SourceHeader:
+		if i.Header != "" {
+			return r.Header.Get(i.Header)
 		}
 	}
 
-	if !ok && i.Subdomain {
-		if v, _ := getSubdomain(r); v != "" {
-			_, index, ok = i.TryMatchString(v)
-		}
+	return ""
+}
+
+// languageContextKey is the request context key of the `WithLanguage`
+// when the I18n.ContextKey is nil.
+type languageContextKey struct{}
+
+func (i *I18n) contextKey() interface{} {
+	if i.ContextKey != nil {
+		return i.ContextKey
 	}
 
-	if !ok {
-		if v := r.Header.Get(acceptLanguageHeaderKey); v != "" {
-			desired, _, err := language.ParseAcceptLanguage(v)
-			if err == nil {
-				if _, idx, conf := i.matcher.Match(desired...); conf > language.Low {
-					index = idx
-				}
-			}
-		}
+	return languageContextKey{}
+}
+
+// WithLanguage is package-level function which calls the `Default.WithLanguage` method.
+//
+// See `I18n#WithLanguage` method for more.
+func WithLanguage(r *http.Request, lang string) *http.Request {
+	return Default.WithLanguage(r, lang)
+}
+
+// WithLanguage returns a shallow copy of "r" which forces the "lang" language,
+// e.g. for an admin preview of another locale.
+// The `GetLocale`, `GetMessage` and `TrContext` of the returned request
+// use that language and skip the cookie, URL parameter, subdomain and header detection.
+//
+// The language is stored under the `ContextKey` or,
+// if that is nil, under a private key, which the rest methods check too.
+func (i *I18n) WithLanguage(r *http.Request, lang string) *http.Request {
+	ctx := context.WithValue(r.Context(), i.contextKey(), lang)
+	ctx = context.WithValue(ctx, localeContextKey{}, (*Locale)(nil)) // overrides the `Middleware`'s one.
+	return r.WithContext(ctx)
+}
+
+// localeContextKey is the request context key of the `Middleware`'s locale.
+type localeContextKey struct{}
+
+// LocaleFromContext returns the locale which the `Middleware` or the `Router` stored in the "ctx",
+// or nil if not found.
+func LocaleFromContext(ctx context.Context) *Locale {
+	loc, _ := ctx.Value(localeContextKey{}).(*Locale)
+	return loc
+}
+
+// LocaleFromRequest returns the locale which the `Middleware` or the `Router` resolved for the "r",
+// e.g. for a request logger, without detecting it again.
+// It reports false if the request was not served through them.
+func LocaleFromRequest(r *http.Request) (*Locale, bool) {
+	loc := LocaleFromContext(r.Context())
+	return loc, loc != nil
+}
+
+// withLocale returns a shallow copy of "r" which stores the "loc" in its context,
+// see `LocaleFromContext`. If the `ContextKey` is not nil then
+// the locale's language code is stored under that key as well.
+func (i *I18n) withLocale(r *http.Request, loc *Locale) *http.Request {
+	if loc == nil {
+		return r
 	}
 
-	// if index == 0 then it defaults to the first language.
-	locale := i.localizer.GetLocale(index)
-	if locale == nil {
-		return nil
+	ctx := context.WithValue(r.Context(), localeContextKey{}, loc)
+	if i.ContextKey != nil {
+		ctx = context.WithValue(ctx, i.ContextKey, loc.Language())
 	}
 
-	return locale
+	return r.WithContext(ctx)
+}
+
+// Middleware is package-level function which calls the `Default.Middleware` method.
+//
+// See `I18n#Middleware` method for more.
+func Middleware(next http.Handler) http.Handler {
+	return Default.Middleware(next)
+}
+
+// Middleware returns a new http handler wrapper which detects the request's locale once
+// and stores it in the request context, so the handlers can retrieve it with the `LocaleFromContext`.
+// The `GetLocale` and `GetMessage` use that locale too, instead of detecting it again.
+// If the `ContextKey` is not nil then the locale's language code is stored under that key as well.
+func (i *I18n) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, i.withLocale(r, i.GetLocale(r)))
+	})
 }
 
 // GetMessage is package-level function which calls the `Default.GetMessage` method.
@@ -435,30 +1804,98 @@ func GetMessage(r *http.Request, format string, args ...interface{}) string {
 }
 
 // GetMessage returns the localized text message for this "r" request based on the key "format".
-// It returns an empty string if locale or format not found.
-func (i *I18n) GetMessage(r *http.Request, format string, args ...interface{}) (msg string) {
-	loc := i.GetLocale(r)
+// It returns an empty string if locale or format not found,
+// unless the DefaultMessageFunc is set, which is called with the request's input language.
+func (i *I18n) GetMessage(r *http.Request, format string, args ...interface{}) string {
+	msg, _ := i.GetMessageError(r, format, args...)
+	return msg
+}
+
+// GetMessageError is package-level function which calls the `Default.GetMessageError` method.
+//
+// See `I18n#GetMessageError` method for more.
+func GetMessageError(r *http.Request, format string, args ...interface{}) (string, error) {
+	return Default.GetMessageError(r, format, args...)
+}
+
+// GetMessageError same as `GetMessage` but it returns an error too.
+// The error wraps the ErrKeyNotFound if the "key" was not found, even on the default language,
+// the ErrLanguageNotMatched if no locale was found for this request
+// or the template's render error. Use the `errors.Is` to check against them.
+//
+// The returned message is always the same as `GetMessage` returns.
+func (i *I18n) GetMessageError(r *http.Request, format string, args ...interface{}) (msg string, err error) {
+	loc, langInput, _ := i.getLocale(r)
 	langMatched := ""
+	var served *Locale
 	if loc != nil {
 		langMatched = loc.Language()
-		// it's not the default/fallback language and not message found for that lang:key.
-		msg = loc.GetMessage(format, args...)
-		if msg == "" && i.DefaultMessageFunc == nil && !i.Strict && loc.Index() > 0 {
-			return i.localizer.GetLocale(0).GetMessage(format, args...)
-		}
+		msg, served, err = i.getMessage(i.getLocalizer(), loc, format, false, func(loc *Locale, r *internal.Resolver) (string, error) {
+			return loc.ResolveMessageError(r, format, args...)
+		})
+	} else {
+		err = ErrLanguageNotMatched
 	}
 
-	if msg == "" && i.DefaultMessageFunc != nil && i.ContextKey != nil {
-		if v := r.Context().Value(i.ContextKey); v != nil {
-			if langInput, ok := v.(string); ok {
-				msg = i.DefaultMessageFunc(langInput, langMatched, format, args...)
-			}
-		}
+	if i.OnLookup != nil {
+		i.OnLookup(langInput, format, newLookupResult(loc, served, loc != nil))
+	}
+
+	if fn := i.getDefaultMessageFunc(); msg == "" && fn != nil {
+		msg = fn(langInput, langMatched, format, args...)
 	}
 
 	return
 }
 
+// JSONHandler is package-level function which calls the `Default.JSONHandler` method.
+//
+// See `I18n#JSONHandler` method for more.
+func JSONHandler() http.Handler {
+	return Default.JSONHandler()
+}
+
+// JSONHandler returns a new http handler which serves the translation messages
+// of the request's language as JSON, see `Locale.MarshalJSON`,
+// e.g. for a client-side i18n library.
+// The "lang" url query parameter requests a specific language, e.g. /i18n?lang=el-GR,
+// it responds with 404 Not Found if that language is not registered.
+//
+// The ETag and Last-Modified headers are based on the load time,
+// so the clients can cache the messages until the next `Reload`.
+func (i *I18n) JSONHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var loc *Locale
+		if lang := r.URL.Query().Get("lang"); lang != "" {
+			if _, index, ok := i.TryMatchString(lang); ok {
+				loc = i.getLocaleByIndex(index)
+			}
+		} else {
+			loc = i.GetLocale(r)
+		}
+
+		if loc == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		b, err := json.Marshal(loc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		i.mu.RLock()
+		loadedAt := i.loadedAt
+		i.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("ETag", fmt.Sprintf(`"%s-%x"`, loc.Language(), loadedAt.UnixNano()))
+		w.Header().Add("Vary", acceptLanguageHeaderKey)
+		http.ServeContent(w, r, "", loadedAt, bytes.NewReader(b))
+	})
+}
+
 // Router is package-level function which calls the `Default.Router` method.
 //
 // See `I18n#Router` method for more.
@@ -466,15 +1903,56 @@ func Router(next http.Handler) http.Handler {
 	return Default.Router(next)
 }
 
+// CookieOptions holds the attributes of the language cookie, see the `I18n.CookieOptions` field.
+// The zero value keeps the default attributes.
+type CookieOptions struct {
+	// Path of the cookie, e.g. "/".
+	// Defaults to empty, the browser uses the request's path.
+	Path string
+	// Domain of the cookie.
+	// Defaults to empty, the domain of the request's host so the subdomains can share it.
+	Domain string
+	// MaxAge of the cookie in seconds.
+	// Defaults to 0, a session cookie.
+	MaxAge int
+	// Secure sends the cookie over HTTPS only.
+	Secure bool
+	// HTTPOnly hides the cookie from the client scripts.
+	HTTPOnly bool
+	// SameSite of the cookie, e.g. http.SameSiteNoneMode (along with Secure) for iframes.
+	// Defaults to 0, the http.SameSiteLaxMode.
+	SameSite http.SameSite
+}
+
+func (i *I18n) setCookie(w http.ResponseWriter, r *http.Request, lang string) {
+	opts := i.CookieOptions
+
+	domain := opts.Domain
+	if domain == "" {
+		// allow subdomain sharing.
+		domain = getDomain(i.getHost(r))
+	}
+
+	sameSite := opts.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     i.Cookie,
+		Value:    lang,
+		Path:     opts.Path,
+		Domain:   domain,
+		MaxAge:   opts.MaxAge,
+		Secure:   opts.Secure,
+		HttpOnly: opts.HTTPOnly,
+		SameSite: sameSite,
+	})
+}
+
 func (i *I18n) setLang(w http.ResponseWriter, r *http.Request, lang string) {
 	if i.Cookie != "" {
-		http.SetCookie(w, &http.Cookie{
-			Name:  i.Cookie,
-			Value: lang,
-			// allow subdomain sharing.
-			Domain:   getDomain(getHost(r)),
-			SameSite: http.SameSiteLaxMode,
-		})
+		i.setCookie(w, r, lang)
 	} else if i.URLParameter != "" {
 		q := r.URL.Query()
 		q.Set(i.URLParameter, lang)
@@ -487,50 +1965,131 @@ func (i *I18n) setLang(w http.ResponseWriter, r *http.Request, lang string) {
 // Router returns a new router wrapper.
 // It compares the path prefix for translated language and
 // local redirects the requested path with the selected (from the path) language to the router.
+//
+// Like the `Middleware`, it stores the resolved locale in the request context,
+// see `LocaleFromRequest`.
 func (i *I18n) Router(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var loc *Locale
 		found := false
-		path := r.URL.Path[1:]
 
-		if idx := strings.IndexByte(path, '/'); idx > 0 {
-			path = path[:idx]
-		}
-
-		if path != "" {
-			if tag, _, ok := i.TryMatchString(path); ok {
-				lang := tag.String()
+		if prefix, tag, ok := i.matchPathPrefix(r.URL.Path); ok {
+			lang := tag.String()
 
-				path = r.URL.Path[len(path)+1:]
-				if path == "" {
-					path = "/"
-				}
-
-				r.RequestURI = path
-				r.URL.Path = path
-				i.setLang(w, r, lang)
-				found = true
+			r.URL.Path = stripPathPrefix(r.URL.Path, prefix)
+			if r.URL.RawPath != "" {
+				r.URL.RawPath = stripPathPrefix(r.URL.RawPath, prefix)
 			}
+
+			i.setLang(w, r, lang)
+			// keep the query string, setLang may modify it.
+			r.RequestURI = r.URL.RequestURI()
+			loc = i.GetLocaleByTag(tag)
+			found = true
 		}
 
 		if !found && i.Subdomain {
-			host := getHost(r)
-			if dotIdx := strings.IndexByte(host, '.'); dotIdx > 0 {
-				if subdomain := host[0:dotIdx]; subdomain != "" {
-					if tag, _, ok := i.TryMatchString(subdomain); ok {
-						host = host[dotIdx+1:]
-						r.URL.Host = host
-						r.Host = host
-						i.setLang(w, r, tag.String())
-					}
+			if subdomain, host := i.getSubdomain(r); subdomain != "" {
+				if tag, index, ok := i.TryMatchString(subdomain); ok {
+					r.URL.Host = host
+					r.Host = host
+					i.setLang(w, r, tag.String())
+					loc = i.getLocaleByIndex(index)
+					found = true
 				}
 			}
 		}
 
-		next.ServeHTTP(w, r)
+		if !found {
+			loc = i.GetLocale(r)
+		}
+
+		next.ServeHTTP(w, i.withLocale(r, loc))
+	})
+}
+
+// RedirectRouter is package-level function which calls the `Default.RedirectRouter` method.
+//
+// See `I18n#RedirectRouter` method for more.
+func RedirectRouter(next http.Handler) http.Handler {
+	return Default.RedirectRouter(next)
+}
+
+// RedirectRouter returns a new router wrapper which, unlike the `Router`,
+// redirects the requests without a language path prefix to the prefixed path
+// of the detected language, e.g. "/some-path" to "/en-us/some-path",
+// so every page has a canonical localized URL.
+// The requests with a language path prefix are served through the `Router`.
+//
+// The requests that the `RedirectSkipper` reports are served as they are, e.g. the assets.
+func (i *I18n) RedirectRouter(next http.Handler) http.Handler {
+	router := i.Router(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := i.matchPathPrefix(r.URL.Path); ok {
+			router.ServeHTTP(w, r)
+			return
+		}
+
+		if i.RedirectSkipper != nil && i.RedirectSkipper(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		loc := i.GetLocale(r)
+		if loc == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		u := *r.URL
+		u.Path = "/" + strings.ToLower(loc.Language()) + r.URL.Path
+		if u.RawPath != "" {
+			u.RawPath = "/" + strings.ToLower(loc.Language()) + r.URL.RawPath
+		}
+
+		http.Redirect(w, r, u.RequestURI(), http.StatusFound)
 	})
 }
 
-func getHost(r *http.Request) string {
+// matchPathPrefix reports whether the first segment of the "path" is a registered language,
+// it returns that segment as a prefix, e.g. "/el-gr", and the matched language tag.
+func (i *I18n) matchPathPrefix(path string) (string, language.Tag, bool) {
+	if len(path) < 2 || path[0] != '/' {
+		return "", language.Und, false
+	}
+
+	segment := path[1:]
+	if idx := strings.IndexByte(segment, '/'); idx >= 0 {
+		segment = segment[:idx]
+	}
+
+	if segment == "" {
+		return "", language.Und, false
+	}
+
+	tag, _, ok := i.TryMatchString(segment)
+	return "/" + segment, tag, ok
+}
+
+// stripPathPrefix removes the language "prefix" of the "path",
+// an empty remainder becomes "/", e.g. "/el-gr" and "/el-gr/" both become "/".
+func stripPathPrefix(path, prefix string) string {
+	path = strings.TrimPrefix(path, prefix)
+	if path == "" {
+		path = "/"
+	}
+
+	return path
+}
+
+func (i *I18n) getHost(r *http.Request) string {
+	if i.TrustForwardedHeaders {
+		if host := forwardedHost(r); host != "" {
+			return host
+		}
+	}
+
 	// contains subdomain.
 	if host := r.URL.Host; host != "" {
 		return host
@@ -538,6 +2097,28 @@ func getHost(r *http.Request) string {
 	return r.Host
 }
 
+// forwardedHost returns the original host of a proxied request,
+// the first one of the X-Forwarded-Host or the host parameter of the Forwarded header,
+// e.g. "el.example.com" of the "for=192.0.2.60;host=el.example.com;proto=https".
+func forwardedHost(r *http.Request) string {
+	if header := r.Header.Get("X-Forwarded-Host"); header != "" {
+		host, _, _ := strings.Cut(header, ",")
+		return strings.TrimSpace(host)
+	}
+
+	if header := r.Header.Get("Forwarded"); header != "" {
+		element, _, _ := strings.Cut(header, ",")
+		for _, pair := range strings.Split(element, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(name, "host") {
+				return strings.Trim(value, `"`)
+			}
+		}
+	}
+
+	return ""
+}
+
 // GetDomain resolves and returns the server's domain.
 func getDomain(hostport string) string {
 	host := hostport
@@ -559,14 +2140,32 @@ func getDomain(hostport string) string {
 	}
 }
 
-func getSubdomain(r *http.Request) (subdomain, host string) {
-	host = getHost(r)
+func (i *I18n) getSubdomain(r *http.Request) (subdomain, host string) {
+	return splitSubdomain(i.getHost(r))
+}
 
-	if index := strings.IndexByte(host, '.'); index > 0 {
-		if subdomain = host[0:index]; subdomain != "" {
-			host = host[index+1:]
-		}
+// splitSubdomain returns the subdomain of the "hostport" and the rest of it,
+// e.g. "el" and "example.com:8080" for the "el.example.com:8080".
+// The IP addresses, e.g. "127.0.0.1" and "[::1]:8080", have no subdomain.
+func splitSubdomain(hostport string) (subdomain, host string) {
+	hostname, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		hostname, port = hostport, ""
 	}
 
-	return
+	if net.ParseIP(strings.Trim(hostname, "[]")) != nil {
+		return "", hostport
+	}
+
+	index := strings.IndexByte(hostname, '.')
+	if index <= 0 {
+		return "", hostport
+	}
+
+	subdomain, host = hostname[:index], hostname[index+1:]
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+
+	return subdomain, host
 }
@@ -0,0 +1,2825 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestTrError(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := i18N.TrError("el-GR", "hi", map[string]string{"Name": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Γειά σου kataras"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// fallback to the default language is not an error.
+	if _, err = i18N.TrError("el-GR", "KeyOnlyOnDefaultLang"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = i18N.TrError("el-GR", "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound but got %v", err)
+	}
+
+	got, err = i18N.TrError("zh-CN", "title")
+	if !errors.Is(err, ErrLanguageNotMatched) {
+		t.Fatalf("expected ErrLanguageNotMatched but got %v", err)
+	}
+	if expected := "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "el-GR")
+	if _, err = i18N.GetMessageError(r, "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound but got %v", err)
+	}
+}
+
+func TestTrVerbose(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang         string
+		key          string
+		expected     string
+		servedLang   string
+		fromFallback bool
+	}{
+		{"el-GR", "title", "Τίτλος", "el-GR", false},
+		{"el-GR", "KeyOnlyOnDefaultLang", "value", "en-US", true},
+		{"zh-CN", "title", "Title", "en-US", true},
+		{"el-GR", "missing", "", "", false},
+	}
+
+	for _, tt := range tests {
+		got, servedLang, fromFallback := i18N.TrVerbose(tt.lang, tt.key)
+		if tt.expected != "" && got != tt.expected {
+			t.Fatalf("[%s:%s] expected message %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+		if servedLang != tt.servedLang {
+			t.Fatalf("[%s:%s] expected served language %q but got %q", tt.lang, tt.key, tt.servedLang, servedLang)
+		}
+		if fromFallback != tt.fromFallback {
+			t.Fatalf("[%s:%s] expected from fallback %v but got %v", tt.lang, tt.key, tt.fromFallback, fromFallback)
+		}
+	}
+}
+
+func TestOnLookup(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	i18N.OnLookup = func(lang, key string, result LookupResult) {
+		got = append(got, lang+":"+key+":"+result.Status.String()+":"+result.Lang)
+	}
+	i18N.URLParameter = "lang"
+
+	i18N.Tr("el-GR", "title")
+	i18N.Tr("el-GR", "KeyOnlyOnDefaultLang")
+	i18N.Tr("zh-CN", "title")
+	i18N.Tr("el-GR", "missing")
+	i18N.GetMessage(httptest.NewRequest("GET", "/?lang=el-GR", nil), "title")
+
+	expected := []string{
+		"el-GR:title:hit:el-GR",
+		"el-GR:KeyOnlyOnDefaultLang:fallback:en-US",
+		"zh-CN:title:fallback:en-US",
+		"el-GR:missing:miss:",
+		"el-GR:title:hit:el-GR",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v but got %v", expected, got)
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "el-GR")
+
+	tests := []struct {
+		translator Translator
+		expected   string
+	}{
+		{i18N, "Τίτλος"},
+		{NopTranslator{}, "title"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.translator.Tr("el-GR", "title"); got != tt.expected {
+			t.Fatalf("[%T] expected %s but got %s", tt.translator, tt.expected, got)
+		}
+
+		if got := tt.translator.GetMessage(r, "title"); got != tt.expected {
+			t.Fatalf("[%T] expected %s but got %s", tt.translator, tt.expected, got)
+		}
+	}
+}
+
+func TestNewStub(t *testing.T) {
+	i18N := NewStub("en-US")
+
+	handler := i18N.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(i18N.GetMessage(r, "hello", "Maria", 2)))
+	}))
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "el-GR")
+	handler.ServeHTTP(rec, r)
+
+	if got, expected := rec.Body.String(), "hello [Maria 2]"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "nav.home"), "nav.home"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if loc := i18N.DefaultLocale(); loc == nil || loc.Language() != "en-US" {
+		t.Fatalf("expected the en-US default locale but got %v", loc)
+	}
+}
+
+func TestSetMessages(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.SetMessage("en-US", "hi", "Hi {{.Name}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.SetMessages("el-GR", Map{"title": "Τίτλος", "cart": Map{"empty": "άδειο"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"en-US", "title", "Title"},
+		{"en-US", "hi", "Hi kataras"},
+		{"el-GR", "title", "Τίτλος"},
+		{"el-GR", "cart.empty", "άδειο"},
+		{"el-GR", "hi", "Hi kataras"}, // fallback to the default language.
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, Map{"Name": "kataras"}); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	i18N, err = New(KV(LangMap{"en-US": Map{"title": "Title"}}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.SetMessage("fr-FR", "title", "Titre"); !errors.Is(err, ErrLanguageNotMatched) {
+		t.Fatalf("expected ErrLanguageNotMatched but got %v", err)
+	}
+
+	// the messages are kept on reload, the failed ones are dropped.
+	if err = i18N.SetMessage("en-US", "hi", "Hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("en-US", "hi"), "Hi"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestSetMessagesLoaders(t *testing.T) {
+	loaders := map[string]Loader{
+		"lazy":      LazyGlob("./testfiles/*/*"),
+		"chain":     Chain(Glob("./testfiles/*/*"), KV(LangMap{"en-US": Map{"extra": "Extra"}})),
+		"namespace": Namespace("app", Glob("./testfiles/*/*")),
+	}
+
+	for name, loader := range loaders {
+		i18N, err := New(loader, "en-US", "el-GR")
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		// the el-GR of the lazy loader is not loaded yet.
+		if err = i18N.SetMessages("el-GR", Map{"title": "Νέος τίτλος", "app.title": "Νέος τίτλος"}); err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		for n := 0; n < 2; n++ {
+			if got, expected := i18N.Tr("el-GR", "title"), "Νέος τίτλος"; got != expected {
+				t.Fatalf("[%s] expected %s but got %s", name, expected, got)
+			}
+		}
+
+		if got, expected := i18N.Tr("el-GR", "app.title"), "Νέος τίτλος"; got != expected {
+			t.Fatalf("[%s] expected %s but got %s", name, expected, got)
+		}
+	}
+
+	// the loaded locales of the lazy loader are not loaded again.
+	var loadedLanguages []string
+	i18N, err := New(Lazy(func(tag language.Tag) (Map, error) {
+		loadedLanguages = append(loadedLanguages, tag.String())
+		return Map{"title": tag.String()}, nil
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N.Tr("el-GR", "title")
+	if err = i18N.SetMessage("el-GR", "hi", "Γειά"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "title")+" "+i18N.Tr("el-GR", "hi"), "el-GR Γειά"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if expected := []string{"en-US", "el-GR"}; !reflect.DeepEqual(loadedLanguages, expected) {
+		t.Fatalf("expected %v to be loaded once but got %v", expected, loadedLanguages)
+	}
+}
+
+func TestSetMessagesRace(t *testing.T) {
+	loaders := []Loader{
+		KV(LangMap{"en-US": Map{"title": "Title"}}),
+		Lazy(func(tag language.Tag) (Map, error) {
+			if tag == language.AmericanEnglish {
+				return Map{"title": "Title"}, nil
+			}
+			return nil, nil
+		}),
+	}
+
+	for _, loader := range loaders {
+		i18N, err := New(loader, "en-US", "el-GR")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		done := make(chan struct{})
+		set := make(chan struct{})
+		go func() {
+			defer close(set)
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if err := i18N.SetMessages("el-GR", Map{"title": "Τίτλος", "key0": "value"}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+
+		var wg sync.WaitGroup
+		for n := 0; n < 4; n++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for k := 0; k < 500; k++ {
+					if got := i18N.Tr("el-GR", "title"); got != "Title" && got != "Τίτλος" {
+						t.Errorf("expected the title but got %q", got)
+						return
+					}
+
+					i18N.Tr("el-GR", "key0")
+				}
+			}()
+		}
+
+		wg.Wait()
+		close(done)
+		<-set
+
+		if got, expected := i18N.Tr("el-GR", "key0"), "value"; got != expected {
+			t.Fatalf("expected %s but got %s", expected, got)
+		}
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(lang, key, expected string) {
+		t.Helper()
+		if got := i18N.Tr(lang, key); got != expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", lang, key, expected, got)
+		}
+	}
+
+	revertA := i18N.Overlay("el-GR", map[string]string{"title": "Τίτλος A"})
+	expect("el-GR", "title", "Τίτλος A")
+	expect("en-US", "title", "Title")
+
+	revertB := i18N.Overlay("el", map[string]string{"title": "Τίτλος B"})
+	expect("el-GR", "title", "Τίτλος B")
+
+	revertDefault := i18N.Overlay("en-US", map[string]string{"KeyOnlyOnDefaultLang": "overridden"})
+	expect("el-GR", "KeyOnlyOnDefaultLang", "overridden") // fallback to the default language.
+	revertDefault()
+	expect("el-GR", "KeyOnlyOnDefaultLang", "value")
+
+	revertB()
+	expect("el-GR", "title", "Τίτλος A")
+	revertB() // no-op.
+	revertA()
+	expect("el-GR", "title", "Τίτλος")
+}
+
+func TestNamedPlaceholders(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"inbox":    "{name}, you have {count} new messages",
+			"bought":   "{name} bought %d items",
+			"missing":  "Hi {name}, {unknown}",
+			"template": "Hi {{.name}}",
+			"printf":   "#!printf Hi {name}",
+			"says":     "{name} says %s",
+			"items": Map{
+				"one":   "{name} has one item",
+				"other": "{name} has %d items",
+			},
+			"labels": Map{
+				"one":   "{name} added one label",
+				"other": "{name} added %d labels: %s",
+			},
+		},
+		"el-GR": Map{
+			"inbox": "{count} νέα μηνύματα για τον {name}",
+		},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "inbox", []interface{}{Map{"name": "kataras", "count": 1000}}, "kataras, you have 1,000 new messages"},
+		{"el-GR", "inbox", []interface{}{map[string]string{"name": "kataras", "count": "3"}}, "3 νέα μηνύματα για τον kataras"},
+		{"en-US", "inbox", nil, "{name}, you have {count} new messages"},
+		{"en-US", "bought", []interface{}{Map{"name": "kataras"}, 3}, "kataras bought 3 items"},
+		{"en-US", "missing", []interface{}{Map{"name": "kataras"}}, "Hi kataras, {unknown}"},
+		{"en-US", "template", []interface{}{Map{"name": "kataras"}}, "Hi kataras"},
+		{"en-US", "printf", []interface{}{Map{"name": "kataras"}}, "Hi {name}"},
+		{"en-US", "bought", []interface{}{3}, "{name} bought 3 items"},
+		// the placeholders of the arguments are not replaced.
+		{"en-US", "says", []interface{}{Map{"name": "kataras", "x": "X"}, "hi {name} {x}"}, "kataras says hi {name} {x}"},
+		{"en-US", "says", []interface{}{"{x}"}, "{name} says {x}"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] %s: expected %q but got %q", i, tt.key, tt.expected, got)
+		}
+	}
+
+	if got, expected := i18N.TrPlural("en-US", "items", 1, Map{"name": "kataras"}), "kataras has one item"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if got, expected := i18N.TrPlural("en-US", "items", 3, Map{"name": "kataras"}), "kataras has 3 items"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if got, expected := i18N.TrPlural("en-US", "labels", 2, Map{"name": "kataras", "x": "X"}, "{x}"), "kataras added 2 labels: {x}"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+}
+
+func TestGender(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"HouseCount": Map{
+				"female": "She (%[2]s) has %[1]d houses",
+				"male":   "He (%[2]s) has %[1]d houses",
+				"other":  "They (%[2]s) have %[1]d houses",
+			},
+			"Welcome": Map{
+				"female": "Welcome {{.Name}}, madam",
+				"male":   "Welcome {{.Name}}, sir",
+			},
+			"Items": Map{
+				"female": Map{
+					"one":   "She has one item",
+					"other": "She has %d items",
+				},
+				"male": "He has %d items",
+			},
+			// not a gender map.
+			"nav": Map{"other": "Other"},
+		},
+	}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"HouseCount", []interface{}{Female, 2, "Maria"}, "She (Maria) has 2 houses"},
+		{"HouseCount", []interface{}{Male, 1, "Peter"}, "He (Peter) has 1 houses"},
+		{"HouseCount", []interface{}{OtherGender, 3, "Alex"}, "They (Alex) have 3 houses"},
+		{"HouseCount", []interface{}{3, "Alex"}, "They (Alex) have 3 houses"},
+		{"HouseCount.female", []interface{}{2, "Maria"}, "She (Maria) has 2 houses"},
+		{"Welcome", []interface{}{Female, Map{"Name": "Maria"}}, "Welcome Maria, madam"},
+		{"Welcome", []interface{}{Map{"Name": "Peter"}, Male}, "Welcome Peter, sir"},
+		{"Items", []interface{}{Female, PluralCount(1)}, "She has one item"},
+		{"Items", []interface{}{Female, PluralCount(5)}, "She has 5 items"},
+		{"Items", []interface{}{Male, PluralCount(5)}, "He has 5 items"},
+		{"nav", []interface{}{PluralCount(1)}, "Other"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr("en-US", tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] %s: expected %q but got %q", i, tt.key, tt.expected, got)
+		}
+	}
+
+	if got, expected := i18N.TrPlural("en-US", "Items", 1, Female), "She has one item"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	if _, err = i18N.TrError("en-US", "Welcome", OtherGender, Map{"Name": "Alex"}); err == nil {
+		t.Fatalf("expected an error for a missing gender form")
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"Finished": "You finished {{ordinal .Rank}}",
+			"Place": Map{
+				"ordinal_one":   "%[1]dst place",
+				"ordinal_two":   "%[1]dnd place",
+				"ordinal_few":   "%[1]drd place",
+				"ordinal_other": "%[1]dth place",
+			},
+		},
+		"el-GR": Map{
+			"Ordinal":  "%dος",
+			"Finished": "Τερμάτισες {{ordinal .Rank}}",
+		},
+		"de-DE": Map{},
+	}), "en-US", "el-GR", "de-DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	en := i18N.LocaleAt(0)
+	for n, expected := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd"} {
+		if got := en.FormatOrdinal(n); got != expected {
+			t.Fatalf("expected %q but got %q", expected, got)
+		}
+	}
+
+	if got, expected := i18N.LocaleAt(1).FormatOrdinal(3), "3ος"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if got, expected := i18N.LocaleAt(2).FormatOrdinal(3), "3"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "Finished", []interface{}{Map{"Rank": 3}}, "You finished 3rd"},
+		{"el-GR", "Finished", []interface{}{Map{"Rank": 3}}, "Τερμάτισες 3ος"},
+		{"en-US", "Place", []interface{}{PluralCount(1)}, "1st place"},
+		{"en-US", "Place", []interface{}{PluralCount(22)}, "22nd place"},
+		{"en-US", "Place", []interface{}{PluralCount(23)}, "23rd place"},
+		{"en-US", "Place", []interface{}{PluralCount(11)}, "11th place"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] %s: expected %q but got %q", i, tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestLiteralPercent(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"el-GR": Map{
+			"discount":   "50% έκπτωση",
+			"escaped":    "100%% σίγουρο",
+			"mixed":      "50% έκπτωση σε %d προϊόντα",
+			"space":      "Κέρδισε 10% σήμερα",
+			"positional": "%[1]d%% από %[2]s",
+			"off":        "50%off",
+			"offMixed":   "50%off σε %d προϊόντα",
+			"items": Map{
+				"one":   "Ένα προϊόν με 50% έκπτωση",
+				"other": "%d προϊόντα με 50% έκπτωση",
+			},
+		},
+	}), "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"discount", nil, "50% έκπτωση"},
+		{"discount", []interface{}{1}, "50% έκπτωση"},
+		{"escaped", nil, "100% σίγουρο"},
+		{"escaped", []interface{}{"ignored"}, "100% σίγουρο"},
+		{"mixed", []interface{}{3}, "50% έκπτωση σε 3 προϊόντα"},
+		{"space", []interface{}{2}, "Κέρδισε 10% σήμερα"},
+		{"positional", []interface{}{20, "σήμερα"}, "20% από σήμερα"},
+		{"off", nil, "50%off"},
+		{"off", []interface{}{3}, "50%off"},
+		{"offMixed", []interface{}{3}, "50%off σε 3 προϊόντα"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr("el-GR", tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] %s: expected %q but got %q", i, tt.key, tt.expected, got)
+		}
+	}
+
+	if got, expected := i18N.TrPlural("el-GR", "items", 1), "Ένα προϊόν με 50% έκπτωση"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if got, expected := i18N.TrPlural("el-GR", "items", 4), "4 προϊόντα με 50% έκπτωση"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+}
+
+func TestInterpolationMarkers(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"template": "#!template Hi",
+			"data":     "#!template\nHi {{.Name}}",
+			"printf":   "#!printf Use {{ .Name }} for %s",
+			"text":     "#!text 100%s {{literal}} ${var}",
+			"unknown":  "#!unknown text",
+			"items": Map{
+				"one":   "#!text {{one}} item",
+				"other": "#!printf %d {{items}}",
+			},
+		},
+	}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"template", nil, "Hi"},
+		{"data", []interface{}{Map{"Name": "kataras"}}, "Hi kataras"},
+		{"printf", []interface{}{"names"}, "Use {{ .Name }} for names"},
+		{"text", []interface{}{"ignored"}, "100%s {{literal}} ${var}"},
+		{"unknown", nil, "#!unknown text"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr("en-US", tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] %s: expected %q but got %q", i, tt.key, tt.expected, got)
+		}
+	}
+
+	if got, expected := i18N.TrPlural("en-US", "items", 1), "{{one}} item"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+	if got, expected := i18N.TrPlural("en-US", "items", 3), "3 {{items}}"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	var b strings.Builder
+	if err = i18N.Export("en-US", "json", &b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"#!text 100%s {{literal}} ${var}"`) {
+		t.Fatalf("expected the exported messages to keep their markers but got:\n%s", b.String())
+	}
+}
+
+func TestExport(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.SetMessage("en-US", "exported.new", "New"); err != nil {
+		t.Fatal(err)
+	}
+
+	revert := i18N.Overlay("en-US", map[string]string{"exported.overlay": "Overlay"})
+	defer revert()
+
+	for _, format := range []string{"yaml", "json", "toml"} {
+		var b strings.Builder
+		if err = i18N.Export("en-US", format, &b); err != nil {
+			t.Fatalf("[%s] %v", format, err)
+		}
+
+		fileSystem := fstest.MapFS{
+			"locales/en-US/exported." + format: &fstest.MapFile{Data: []byte(b.String())},
+		}
+
+		loader, err := FS(fileSystem, "locales/*/*")
+		if err != nil {
+			t.Fatalf("[%s] %v", format, err)
+		}
+
+		exported, err := New(loader, "en-US")
+		if err != nil {
+			t.Fatalf("[%s] %v\n%s", format, err, b.String())
+		}
+
+		for _, key := range i18N.Keys("en-US") {
+			if expected, got := i18N.Tr("en-US", key), exported.Tr("en-US", key); expected != got {
+				t.Fatalf("[%s] %s: expected %q but got %q", format, key, expected, got)
+			}
+		}
+
+		for key, expected := range map[string]string{"exported.new": "New", "exported.overlay": "Overlay"} {
+			if got := exported.Tr("en-US", key); got != expected {
+				t.Fatalf("[%s] %s: expected %q but got %q", format, key, expected, got)
+			}
+		}
+	}
+
+	if err = i18N.Export("en-US", "xml", &strings.Builder{}); err == nil {
+		t.Fatalf("expected an unsupported format error")
+	}
+
+	if err = i18N.Export("fr-FR", "json", &strings.Builder{}); err == nil {
+		t.Fatalf("expected a language not found error")
+	}
+}
+
+func TestTrTag(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tag      language.Tag
+		expected string
+	}{
+		{language.MustParse("el-GR"), "Γειά σου kataras"},
+		{language.Greek, "Γειά σου kataras"},
+		{language.AmericanEnglish, "Hi kataras"},
+		// not matched, the default language.
+		{language.Japanese, "Hi kataras"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.TrTag(tt.tag, "hi", map[string]string{"Name": "kataras"}); got != tt.expected {
+			t.Fatalf("[%d] expected %q but got %q", i, tt.expected, got)
+		}
+	}
+
+	if loc := i18N.GetLocaleByTag(language.Greek); loc == nil || loc.Language() != "el-GR" {
+		t.Fatalf("expected the el-GR locale but got %v", loc)
+	}
+
+	if loc := i18N.GetLocaleByTag(language.Japanese); loc != nil {
+		t.Fatalf("expected a nil locale but got %v", loc)
+	}
+}
+
+func TestLocaleAt(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var langs []string
+	for index := 0; ; index++ {
+		loc := i18N.LocaleAt(index)
+		if loc == nil {
+			break
+		}
+
+		langs = append(langs, loc.Language())
+	}
+
+	if expected := []string{"en-US", "el-GR"}; !reflect.DeepEqual(langs, expected) {
+		t.Fatalf("expected %v but got %v", expected, langs)
+	}
+
+	var all []string
+	for _, loc := range i18N.AllLocales() {
+		all = append(all, loc.Language())
+	}
+
+	if !reflect.DeepEqual(all, langs) {
+		t.Fatalf("expected %v but got %v", langs, all)
+	}
+
+	if loc := i18N.LocaleAt(-1); loc != nil {
+		t.Fatalf("expected a nil locale but got %v", loc)
+	}
+
+	if loc := i18N.DefaultLocale(); loc == nil || loc.Language() != "en-US" {
+		t.Fatalf("expected the en-US default locale but got %v", loc)
+	}
+
+	i18N.SetDefault("el-GR")
+	if loc := i18N.DefaultLocale(); loc == nil || loc.Language() != "el-GR" {
+		t.Fatalf("expected the el-GR default locale but got %v", loc)
+	}
+
+	// the indexes are not reshuffled.
+	for index, expected := range []string{"en-US", "el-GR"} {
+		if loc := i18N.LocaleAt(index); loc == nil || loc.Language() != expected {
+			t.Fatalf("[%d] expected the %s locale but got %v", index, expected, loc)
+		}
+	}
+
+	if i18N.SetDefault("zh-CN") {
+		t.Fatalf("expected a not registered language to not be the default one")
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	defaultMessageFunc := func(langInput, langMatched, key string, args ...interface{}) string {
+		return "missing: " + key
+	}
+
+	i18N, err := NewWithOptions(Glob("./testfiles/*/*"),
+		WithLanguages("en-US", "el-GR"),
+		WithDefault("el-GR"),
+		WithDefaultMessageFunc(defaultMessageFunc),
+		WithCookie("lang"),
+		WithURLParameter("hl"),
+		WithSubdomain(),
+		WithStrict(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !i18N.Strict || i18N.Cookie != "lang" || i18N.URLParameter != "hl" || !i18N.Subdomain {
+		t.Fatalf("expected the options to set the fields")
+	}
+
+	if got, expected := i18N.DefaultLocale().Language(), "el-GR"; got != expected {
+		t.Fatalf("expected the %s default language but got %s", expected, got)
+	}
+
+	if got, expected := i18N.Tr("zh-CN", "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// the order of the languages is kept.
+	if got, expected := i18N.LocaleAt(0).Language(), "en-US"; got != expected {
+		t.Fatalf("expected the %s first language but got %s", expected, got)
+	}
+
+	if got, expected := i18N.GetMessage(httptest.NewRequest("GET", "/", nil), "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// the locales use the DefaultMessageFunc too.
+	if got, expected := i18N.LocaleAt(1).GetMessage("missing"), "missing: missing"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// the languages are parsed by the loader.
+	i18N, err = NewWithOptions(Glob("./testfiles/*/*"), WithDefault("el-GR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.DefaultLocale().Language(), "el-GR"; got != expected {
+		t.Fatalf("expected the %s default language but got %s", expected, got)
+	}
+
+	if _, err = NewWithOptions(Glob("./testfiles/*/*"), WithDefault("zh-CN")); !errors.Is(err, ErrLanguageNotMatched) {
+		t.Fatalf("expected ErrLanguageNotMatched but got %v", err)
+	}
+}
+
+func TestExists(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected bool
+	}{
+		{"en-US", "KeyOnlyOnDefaultLang", true},
+		{"el-GR", "KeyOnlyOnDefaultLang", false},
+		{"el-GR", "cart.after.thanks", true},
+		{"el-GR", "cart.after", false},
+		{"zh-CN", "title", false},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Exists(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %v but got %v", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"JSONTemplateExample",
+		"KeyOnlyOnDefaultLang",
+		"TypeOf",
+		"buy",
+		"cart.after.thanks",
+		"cart.checkout",
+		"hello",
+		"hi",
+		"int",
+		"title",
+	}
+
+	got := i18N.Keys("en-US")
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v but got %v", expected, got)
+	}
+
+	if got = i18N.Keys("zh-CN"); got != nil {
+		t.Fatalf("expected nil but got %v", got)
+	}
+}
+
+func TestMissingKeys(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := i18N.MissingKeys("en-US", "el-GR")
+	if expected := []string{"KeyOnlyOnDefaultLang", "hello"}; !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v but got %v", expected, got)
+	}
+
+	if got = i18N.MissingKeys("el-GR", "en-US"); len(got) > 0 {
+		t.Fatalf("expected no missing keys but got %v", got)
+	}
+}
+
+func TestTrContext(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), "lang", "el-GR")
+
+	// ContextKey is nil, use the default language.
+	got := i18N.TrContext(ctx, "title")
+	if expected := "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	i18N.ContextKey = "lang"
+	got = i18N.TrContext(ctx, "title")
+	if expected := "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	got = i18N.TrContext(context.Background(), "title")
+	if expected := "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestTrPlural(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"files": Map{
+				"one":   "%d file",
+				"other": "%d files",
+			},
+			"dogs": Map{
+				"zero":  "{{.Name}} has no dogs",
+				"one":   "{{.Name}} has one dog",
+				"other": "{{.Name}} has {{.Dogs}} dogs",
+			},
+			"hello": "Hello %d",
+		},
+	}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		count    int
+		args     []interface{}
+		expected string
+	}{
+		{"files", 1, nil, "1 file"},
+		{"files", 2, nil, "2 files"},
+		{"dogs", 0, []interface{}{Map{"Name": "Maria", "Dogs": 0}}, "Maria has no dogs"},
+		{"dogs", 1, []interface{}{Map{"Name": "Maria", "Dogs": 1}}, "Maria has one dog"},
+		// the data's integer fields do not confuse the plural count.
+		{"dogs", 3, []interface{}{Map{"Name": "Maria", "Dogs": 3, "Age": 1}}, "Maria has 3 dogs"},
+		{"hello", 5, nil, "Hello 5"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.TrPlural("en-US", tt.key, tt.count, tt.args...); got != tt.expected {
+			t.Fatalf("[%s:%d] expected %s but got %s", tt.key, tt.count, tt.expected, got)
+		}
+	}
+}
+
+func TestPluralCountKey(t *testing.T) {
+	options := DefaultLoaderConfig
+	options.PluralCountKey = "Count"
+
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"files": Map{
+				"one":   "%d file",
+				"other": "%d files",
+			},
+			"dogs": Map{
+				"one":   "{{.Name}} has one dog",
+				"other": "{{.Name}} has {{.Count}} dogs",
+			},
+		},
+	}, options), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"dogs", []interface{}{Map{"Name": "Maria", "Count": 1}}, "Maria has one dog"},
+		{"dogs", []interface{}{Map{"Name": "Maria", "Count": 3, "PluralCount": 1}}, "Maria has 3 dogs"},
+		{"dogs", []interface{}{Map{"Name": "Maria", "Count": float64(3)}}, "Maria has 3 dogs"}, // e.g. decoded JSON.
+		{"dogs", []interface{}{map[string]string{"Name": "Maria", "Count": "1"}}, "Maria has one dog"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr("en-US", tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.key, tt.expected, got)
+		}
+	}
+
+	// the positional integer is not a plural count by default.
+	if _, err = i18N.TrError("en-US", "files", 2); err == nil {
+		t.Fatalf("expected missing plural count error")
+	}
+
+	if got, expected := i18N.TrPlural("en-US", "files", 2), "2 files"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestPluralCountArgument(t *testing.T) {
+	langMap := LangMap{
+		"en-US": Map{
+			"files": Map{
+				"one":   "%d file",
+				"other": "%d files",
+			},
+			"FreeDay": Map{
+				"one":   "{{.Name}} has one free day",
+				"other": "{{.Name}} has {{.Days}} free days",
+			},
+			"HouseCount": Map{
+				"female": "She has %d houses",
+				"other":  "They have %d houses",
+			},
+		},
+	}
+	data := Map{"Name": "Maria", "Days": 5}
+
+	for _, positional := range []bool{true, false} {
+		options := DefaultLoaderConfig
+		options.PositionalPluralCount = positional
+
+		i18N, err := New(KV(langMap, options), "en-US")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			key      string
+			args     []interface{}
+			expected string
+		}{
+			{"files", []interface{}{PluralCount(1)}, "1 file"},
+			{"files", []interface{}{PluralCount(2)}, "2 files"},
+			{"FreeDay", []interface{}{PluralCount(1), data}, "Maria has one free day"},
+			{"FreeDay", []interface{}{data, PluralCount(5)}, "Maria has 5 free days"},
+			{"FreeDay", []interface{}{Map{"Name": "Maria", "Days": 1, "PluralCount": PluralCount(5)}}, "Maria has 1 free days"},
+			{"HouseCount", []interface{}{Female, PluralCount(2)}, "She has 2 houses"},
+		}
+
+		for _, tt := range tests {
+			got, err := i18N.TrError("en-US", tt.key, tt.args...)
+			if err != nil {
+				t.Fatalf("[%v:%s] %v", positional, tt.key, err)
+			}
+
+			if got != tt.expected {
+				t.Fatalf("[%v:%s] expected %q but got %q", positional, tt.key, tt.expected, got)
+			}
+		}
+	}
+}
+
+func TestPositionalPluralCount(t *testing.T) {
+	langMap := LangMap{
+		"en-US": Map{
+			"files": Map{
+				"one":   "%d file",
+				"other": "%d files",
+			},
+		},
+	}
+
+	// a LoaderConfig literal, e.g. with custom delimiters only, treats the integers as data.
+	i18N, err := New(KV(langMap, LoaderConfig{Left: "[[", Right: "]]"}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = i18N.TrError("en-US", "files", 2); err == nil {
+		t.Fatalf("expected missing plural count error")
+	}
+
+	i18N, err = New(KV(langMap, LoaderConfig{Left: "[[", Right: "]]", PositionalPluralCount: true}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, count := range []interface{}{1, int64(2), uint8(2)} {
+		expected := "2 files"
+		if count == 1 {
+			expected = "1 file"
+		}
+
+		got, err := i18N.TrError("en-US", "files", count)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != expected {
+			t.Fatalf("expected %q but got %q", expected, got)
+		}
+	}
+
+	// only the integer types are positional counts.
+	for _, count := range []interface{}{"42", float64(2)} {
+		if _, err = i18N.TrError("en-US", "files", count); err == nil {
+			t.Fatalf("[%v] expected missing plural count error", count)
+		}
+	}
+}
+
+func TestMissingKey(t *testing.T) {
+	langMap := LangMap{
+		"en-US": Map{"hi": "Hi {{.Naem}}"},
+	}
+	data := map[string]interface{}{"Name": "Maria"}
+
+	i18N, err := New(KV(langMap), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the lenient behavior is kept by default.
+	if _, err = i18N.TrError("en-US", "hi", data); err != nil {
+		t.Fatal(err)
+	}
+
+	options := DefaultLoaderConfig
+	options.MissingKey = "error"
+	i18N, err = New(KV(langMap, options), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = i18N.TrError("en-US", "hi", data); err == nil || !strings.Contains(err.Error(), "Naem") {
+		t.Fatalf("expected a missing key error but got %v", err)
+	}
+
+	options.MissingKey = "unknown"
+	if _, err = New(KV(langMap, options), "en-US"); err == nil {
+		t.Fatalf("expected an invalid missing key option error")
+	}
+}
+
+func TestPluralFunc(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"files": Map{
+				"one":   "%d file",
+				"few":   "%d files (a few)",
+				"=0":    "no files",
+				"other": "%d files",
+			},
+			"category": "{{pluralCategory .}}",
+		},
+	}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("en-US", "files", PluralCount(3)), "3 files"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	i18N.PluralFunc = func(tag language.Tag, count interface{}) string {
+		switch n := count.(int); {
+		case n == 1:
+			return "one"
+		case n > 1 && n < 10:
+			return "few"
+		default:
+			return "other"
+		}
+	}
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		count    int
+		expected string
+	}{
+		{0, "no files"},
+		{1, "1 file"},
+		{3, "3 files (a few)"},
+		{12, "12 files"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr("en-US", "files", PluralCount(tt.count)); got != tt.expected {
+			t.Fatalf("[%d] expected %s but got %s", tt.count, tt.expected, got)
+		}
+	}
+
+	if got, expected := i18N.Tr("en-US", "category", 5), "few"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestTrPluralCategories(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"ru": Map{
+			"files": Map{
+				"one":   "%d файл",
+				"few":   "%d файла",
+				"many":  "%d файлов",
+				"other": "%d файла",
+			},
+		},
+		"ar": Map{
+			"days": Map{
+				"zero":  "zero",
+				"one":   "one",
+				"two":   "two",
+				"few":   "few",
+				"many":  "many",
+				"other": "other",
+			},
+		},
+	}), "ru", "ar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		count    int
+		expected string
+	}{
+		{"ru", "files", 1, "1 файл"},
+		{"ru", "files", 21, "21 файл"},
+		{"ru", "files", 3, "3 файла"},
+		{"ru", "files", 5, "5 файлов"},
+		{"ru", "files", 11, "11 файлов"},
+		{"ar", "days", 0, "zero"},
+		{"ar", "days", 1, "one"},
+		{"ar", "days", 2, "two"},
+		{"ar", "days", 5, "few"},
+		{"ar", "days", 11, "many"},
+		{"ar", "days", 100, "other"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.TrPlural(tt.lang, tt.key, tt.count); got != tt.expected {
+			t.Fatalf("[%s:%s:%d] expected %s but got %s", tt.lang, tt.key, tt.count, tt.expected, got)
+		}
+
+		// the same through a PluralCount argument.
+		if got := i18N.Tr(tt.lang, tt.key, PluralCount(tt.count)); got != tt.expected {
+			t.Fatalf("[%s:%s:%d] expected %s but got %s", tt.lang, tt.key, tt.count, tt.expected, got)
+		}
+	}
+}
+
+func TestNoFallbackPrefixes(t *testing.T) {
+	options := DefaultLoaderConfig
+	options.NoFallbackPrefixes = []string{"legal."}
+
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"title": "Title",
+			"legal": Map{"tos": "Terms of Service"},
+		},
+		"el-GR": Map{},
+	}, options), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "title"), "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	got, err := i18N.TrError("el-GR", "legal.tos")
+	if !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound but got %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected empty message but got %s", got)
+	}
+
+	if got, expected := i18N.Tr("en-US", "legal.tos"), "Terms of Service"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestDefaultIndexFallback(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title"},
+		"el-GR": Map{"title": "Τίτλος", "onlyGreek": "Μόνο"},
+		"de-DE": Map{"title": "Titel"},
+	}), "en-US", "el-GR", "de-DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !i18N.SetDefault("el-GR") {
+		t.Fatalf("expected the el-GR to be the default language")
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"de-DE", "onlyGreek", "Μόνο"}, // the key falls back to the default language, not the first one.
+		{"en-US", "onlyGreek", "Μόνο"},
+		{"zh-CN", "title", "Τίτλος"}, // the language falls back to the default language.
+		{"de-DE", "title", "Titel"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "de-DE")
+	if got, expected := i18N.GetMessage(r, "onlyGreek"), "Μόνο"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// the default index is kept on reload.
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("zh-CN", "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestTemplateReferenceDefault(t *testing.T) {
+	i18N, err := NewWithOptions(KV(LangMap{
+		"en-US": Map{"title": "Title", "page": "{{tr \"title\"}}"},
+		"el-GR": Map{"title": "Τίτλος", "brand": "Μάρκα", "page": "{{tr \"title\"}}"},
+		"de-DE": Map{"page": "{{tr \"brand\"}}: {{tr \"title\"}}"},
+	}), WithLanguages("en-US", "el-GR", "de-DE"), WithDefault("el-GR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the references fallback to the default language, not the first one.
+	if got, expected := i18N.Tr("de-DE", "page"), "Μάρκα: Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if got, expected := i18N.Tr("en-US", "page"), "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestTrStrict(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"el-GR", "title", "Τίτλος"},
+		{"el-GR", "KeyOnlyOnDefaultLang", ""},
+		{"zh-CN", "title", ""},
+		{"en-US", "KeyOnlyOnDefaultLang", "value"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.TrStrict(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	// the instance-wide lookups still fallback.
+	if got, expected := i18N.Tr("el-GR", "KeyOnlyOnDefaultLang"), "value"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestSetFallback(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title", "color": "color", "hello": "Hello"},
+		"pt-PT": Map{"title": "Título", "color": "cor"},
+		"pt-BR": Map{"title": "Título"},
+		"es-ES": Map{"hello": "Hola"},
+	}), "en-US", "pt-PT", "pt-BR", "es-ES")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("pt-BR", "color"), "color"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if !i18N.SetFallback("pt-BR", "pt-PT", "en-US") {
+		t.Fatalf("expected fallback to be set")
+	}
+
+	// cycle.
+	if !i18N.SetFallback("pt-PT", "es-ES", "pt-BR") {
+		t.Fatalf("expected fallback to be set")
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"pt-BR", "color", "cor"},
+		{"pt-BR", "hello", "Hola"},
+		{"pt-PT", "hello", "Hola"},
+		{"es-ES", "title", "Title"},
+		{"pt-BR", "missing", ""},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	if i18N.SetFallback("pt-BR", "zh-CN") {
+		t.Fatalf("expected unregistered fallback language to fail")
+	}
+}
+
+func TestTemplateReferenceFallback(t *testing.T) {
+	langs := LangMap{
+		"en-US": Map{"title": "Title", "color": "color"},
+		"pt-PT": Map{"color": "cor"},
+		"pt-BR": Map{"page": "{{tr \"color\"}} - {{tr \"title\"}}"},
+	}
+
+	i18N, err := New(KV(langs), "en-US", "pt-PT", "pt-BR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !i18N.SetFallback("pt-BR", "pt-PT") {
+		t.Fatalf("expected fallback to be set")
+	}
+
+	// the references follow the fallback chain, then the default language.
+	if got, expected := i18N.Tr("pt-BR", "page"), "cor - Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// the strict lookups do not resolve the references of other languages.
+	if got, expected := i18N.TrStrict("pt-BR", "page"), " - "; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+
+	i18N, err = NewWithOptions(KV(langs), WithLanguages("en-US", "pt-PT", "pt-BR"), WithStrict())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("pt-BR", "page"), " - "; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+}
+
+type closerLocalizer struct {
+	Localizer
+	closed *int
+}
+
+func (l closerLocalizer) Close() error {
+	*l.closed++
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	closed := 0
+	loader := func(m *Matcher) (Localizer, error) {
+		localizer, err := KV(LangMap{"en-US": Map{"hi": "hello"}})(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return closerLocalizer{Localizer: localizer, closed: &closed}, nil
+	}
+
+	for _, l := range []Loader{loader, Chain(KV(LangMap{"en-US": Map{"bye": "goodbye"}}), loader)} {
+		closed = 0
+
+		i18N, err := New(l, "en-US")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = i18N.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if closed != 1 {
+			t.Fatalf("expected the localizer to be closed once but got %d", closed)
+		}
+
+		if got, expected := i18N.Tr("en-US", "hi"), "hello"; got != expected {
+			t.Fatalf("expected %q but got %q", expected, got)
+		}
+	}
+
+	i18N, err := New(KV(LangMap{"en-US": Map{"hi": "hello"}}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.Close(); err != nil {
+		t.Fatalf("expected no error of a localizer without Close but got: %v", err)
+	}
+}
+
+// TestReloadRace should run with the -race flag.
+func TestReloadRace(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"hi": "hello", "bye": "goodbye"},
+		"el-GR": Map{"hi": "γειά"},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N.URLParameter = "lang"
+	if !i18N.SetFallback("el-GR", "en-US") {
+		t.Fatalf("expected the el-GR fallback to be set")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?lang=el-GR", nil)
+
+	done := make(chan struct{})
+	reloaded := make(chan struct{})
+	go func() {
+		defer close(reloaded)
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if err := i18N.Reload(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for n := 0; n < 4; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for k := 0; k < 500; k++ {
+				if got, expected := i18N.Tr("el-GR", "bye"), "goodbye"; got != expected {
+					t.Errorf("expected %q but got %q", expected, got)
+					return
+				}
+
+				if loc := i18N.GetLocale(r); loc == nil || loc.Language() != "el-GR" {
+					t.Errorf("expected the el-GR locale but got %v", loc)
+					return
+				}
+
+				if got, expected := i18N.GetMessage(r, "hi"), "γειά"; got != expected {
+					t.Errorf("expected %q but got %q", expected, got)
+					return
+				}
+
+				i18N.AllLocales()
+				i18N.DefaultLocale()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	<-reloaded
+}
+
+func TestGetMessageDefaultMessageFunc(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.URLParameter = "lang"
+	i18N.DefaultMessageFunc = func(langInput, langMatched, key string, args ...interface{}) string {
+		return langInput + ":" + langMatched + ":" + key
+	}
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{"/?lang=el-GR", "el-GR:el-GR:missing"},
+		{"/?lang=zh-CN", "zh-CN:en-US:missing"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if got := i18N.GetMessage(r, "missing"); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.target, tt.expected, got)
+		}
+	}
+}
+
+// TestLocaleDefaultMessageFunc covers the DefaultMessageFunc of the locales,
+// which is passed to them by the Matcher on load, e.g. for the Locale.GetMessage
+// that does not go through the I18n.
+func TestLocaleDefaultMessageFunc(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := i18N.LocaleAt(1)
+	if got := loc.GetMessage("missing"); got != "" {
+		t.Fatalf("expected an empty message but got %s", got)
+	}
+
+	i18N.DefaultMessageFunc = func(langInput, langMatched, key string, args ...interface{}) string {
+		return langInput + ":" + langMatched + ":" + key
+	}
+
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	loc = i18N.LocaleAt(1)
+	if got, expected := loc.GetMessage("missing"), "el-GR:el-GR:missing"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestSetDefaultMessageFunc(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := i18N.Tr("el-GR", "missing"); got != "" {
+		t.Fatalf("expected an empty message but got %s", got)
+	}
+
+	i18N.SetDefaultMessageFunc(func(langInput, langMatched, key string, args ...interface{}) string {
+		return langInput + ":" + langMatched + ":" + key
+	})
+
+	if got, expected := i18N.Tr("el-GR", "missing"), "el-GR:el-GR:missing"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	r := httptest.NewRequest("GET", "/?lang=el-GR", nil)
+	i18N.URLParameter = "lang"
+	if got, expected := i18N.GetMessage(r, "missing"), "el-GR:el-GR:missing"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.LocaleAt(1).GetMessage("missing"), "el-GR:el-GR:missing"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestHumanizeKeyMessageFunc(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"nav.more.what", "What"},
+		{"user.firstName", "First Name"},
+		{"errors.not_found", "Not Found"},
+		{"page-title", "Page Title"},
+		{"HTTPError", "HTTP Error"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := HumanizeKeyMessageFunc("en-US", "en-US", tt.key); got != tt.expected {
+			t.Fatalf("[%s] expected %q but got %q", tt.key, tt.expected, got)
+		}
+	}
+
+	i18N, err := NewWithOptions(Glob("./testfiles/*/*"), WithLanguages("en-US", "el-GR"), WithDefaultMessageFunc(HumanizeKeyMessageFunc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "nav.more.what"), "What"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestLogMissingOnce(t *testing.T) {
+	var logged []string
+	fn := LogMissingOnce(func(lang, key string) {
+		logged = append(logged, lang+":"+key)
+	}, HumanizeKeyMessageFunc)
+
+	i18N, err := NewWithOptions(Glob("./testfiles/*/*"), WithLanguages("en-US", "el-GR"), WithDefaultMessageFunc(fn))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n := 0; n < 3; n++ {
+		if got, expected := i18N.Tr("el-GR", "nav.more"), "More"; got != expected {
+			t.Fatalf("expected %s but got %s", expected, got)
+		}
+		i18N.Tr("en-US", "nav.more")
+	}
+
+	if expected := []string{"el-GR:nav.more", "en-US:nav.more"}; !reflect.DeepEqual(logged, expected) {
+		t.Fatalf("expected %v but got %v", expected, logged)
+	}
+
+	// the unmatched input languages are logged once, as "und".
+	logged = nil
+	for _, langInput := range []string{"xx-YY", "zz", "a-random-input"} {
+		if got, expected := fn(langInput, "", "nav.more"), "More"; got != expected {
+			t.Fatalf("expected %s but got %s", expected, got)
+		}
+	}
+
+	if expected := []string{"und:nav.more"}; !reflect.DeepEqual(logged, expected) {
+		t.Fatalf("expected %v but got %v", expected, logged)
+	}
+
+	if got := LogMissingOnce(nil, nil)("en-US", "en-US", "key"); got != "" {
+		t.Fatalf("expected an empty message but got %s", got)
+	}
+}
+
+func TestDefaultMessageFuncWithoutLocale(t *testing.T) {
+	i18N := &I18n{
+		URLParameter: "lang",
+		DefaultMessageFunc: func(langInput, langMatched, key string, args ...interface{}) string {
+			return langInput + ":" + langMatched + ":" + key
+		},
+	}
+
+	if got, expected := i18N.Tr("el-GR", "hello"), "el-GR::hello"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	r := httptest.NewRequest("GET", "/?lang=el-GR", nil)
+	if got, expected := i18N.GetMessage(r, "hello"), "el-GR::hello"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if _, err := i18N.GetMessageError(r, "hello"); !errors.Is(err, ErrLanguageNotMatched) {
+		t.Fatalf("expected ErrLanguageNotMatched but got %v", err)
+	}
+}
+
+func TestWithLanguage(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Cookie = "lang"
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "lang", Value: "en-US"})
+	r = i18N.WithLanguage(r, "el-GR")
+
+	if got, expected := i18N.GetMessage(r, "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if got, expected := i18N.TrContext(r.Context(), "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	i18N.ContextKey = "language"
+	r = i18N.WithLanguage(r, "en-US")
+	if got := r.Context().Value("language"); got != "en-US" {
+		t.Fatalf("expected ContextKey value to be set but got %v", got)
+	}
+
+	if got, expected := i18N.GetMessage(r, "title"), "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		target             string
+		expectedPath       string
+		expectedRequestURI string
+		expectedMessage    string
+	}{
+		{"/el-gr/some-path?foo=bar", "/some-path", "/some-path?foo=bar", "Τίτλος"},
+		{"/el-gr/some-path/?foo=bar", "/some-path/", "/some-path/?foo=bar", "Τίτλος"},
+		{"/el-gr", "/", "/", "Τίτλος"},
+		{"/el-gr/?foo=bar", "/", "/?foo=bar", "Τίτλος"},
+		{"/some-path?foo=bar", "/some-path", "/some-path?foo=bar", "Title"},
+	}
+
+	for _, tt := range tests {
+		var path, requestURI, msg string
+		handler := i18N.Router(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			requestURI = r.RequestURI
+			msg = i18N.GetMessage(r, "title")
+
+			if got := r.URL.Query().Get("foo"); r.URL.RawQuery != "" && got != "bar" {
+				t.Fatalf("[%s] expected query to be kept but got %s", tt.target, r.URL.RawQuery)
+			}
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.target, nil))
+
+		if path != tt.expectedPath {
+			t.Fatalf("[%s] expected path %s but got %s", tt.target, tt.expectedPath, path)
+		}
+		if requestURI != tt.expectedRequestURI {
+			t.Fatalf("[%s] expected request URI %s but got %s", tt.target, tt.expectedRequestURI, requestURI)
+		}
+		if msg != tt.expectedMessage {
+			t.Fatalf("[%s] expected message %s but got %s", tt.target, tt.expectedMessage, msg)
+		}
+	}
+}
+
+func TestTryMatchStringConfidence(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title"},
+		"es":    Map{"title": "Título"},
+	}), "en-US", "es")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input         string
+		expectedIndex int
+		expectedConf  language.Confidence
+	}{
+		{"en-US", 0, language.Exact},
+		{"es", 1, language.Exact},
+		{"es-419", 1, language.High},
+		{"zh-CN", 0, language.No},
+		{"?", -1, language.No},
+	}
+
+	for _, tt := range tests {
+		_, index, conf := i18N.TryMatchStringConfidence(tt.input)
+		if index != tt.expectedIndex {
+			t.Fatalf("[%s] expected index %d but got %d", tt.input, tt.expectedIndex, index)
+		}
+		if conf != tt.expectedConf {
+			t.Fatalf("[%s] expected confidence %s but got %s", tt.input, tt.expectedConf, conf)
+		}
+
+		if _, _, ok := i18N.TryMatchString(tt.input); ok != (conf > language.Low) {
+			t.Fatalf("[%s] expected TryMatchString to report %v", tt.input, conf > language.Low)
+		}
+	}
+}
+
+func TestRegionFallback(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"el-GR":      Map{"title": "Τίτλος"},
+		"en-US":      Map{"title": "Title"},
+		"pt-BR":      Map{"title": "Título"},
+		"zh-Hant-TW": Map{"title": "標題"},
+	}), "el-GR", "en-US", "pt-BR", "zh-Hant-TW")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"en-GB", "en-US"},
+		{"en-AU", "en-US"},
+		{"en", "en-US"},
+		{"pt-PT", "pt-BR"},
+		{"zh-HK", "zh-Hant-TW"},
+		{"el-CY", "el-GR"},
+	}
+
+	for _, tt := range tests {
+		tag, _, ok := i18N.TryMatchString(tt.input)
+		if !ok {
+			t.Fatalf("[%s] expected a match", tt.input)
+		}
+		if got := tag.String(); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.input, tt.expected, got)
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", tt.input)
+		if got := i18N.GetLocale(r).Language(); got != tt.expected {
+			t.Fatalf("[%s] expected locale %s but got %s", tt.input, tt.expected, got)
+		}
+	}
+
+	if !i18N.SetDefault("en-GB") {
+		t.Fatalf("expected en-GB to set the en-US as the default language")
+	}
+	if got, expected := i18N.Tr("fr-FR", "title"), "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestMinConfidence(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US":      Map{"title": "Title"},
+		"zh-Hant-TW": Map{"title": "標題"},
+	}), "en-US", "zh-Hant-TW")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		minConfidence language.Confidence
+		input         string
+		expected      bool
+		expectedLang  string
+	}{
+		{language.Low, "zh", false, "en-US"},
+		{language.Low, "en-GB", true, "en-US"},
+		{language.No, "zh", true, "zh-Hant-TW"},
+		{language.High, "en-GB", false, "en-US"},
+		{language.High, "en", true, "en-US"},
+	}
+
+	for _, tt := range tests {
+		i18N.MinConfidence = tt.minConfidence
+		if _, _, ok := i18N.TryMatchString(tt.input); ok != tt.expected {
+			t.Fatalf("[%s:%s] expected match %v but got %v", tt.minConfidence, tt.input, tt.expected, ok)
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", tt.input)
+		if got := i18N.GetLocale(r).Language(); got != tt.expectedLang {
+			t.Fatalf("[%s:%s] expected locale %s but got %s", tt.minConfidence, tt.input, tt.expectedLang, got)
+		}
+	}
+}
+
+func TestMatchAcceptLanguage(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title"},
+		"fr-FR": Map{"title": "Titre"},
+		"de-DE": Map{"title": "Titel"},
+	}), "en-US", "fr-FR", "de-DE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		header          string
+		expectedIndex   int
+		expectedConf    language.Confidence
+		expectedMessage string
+	}{
+		{"fr;q=0.2, de;q=0.9", 2, language.Exact, "Titel"},
+		{"fr-FR;q=0.2, de-DE;q=0.9", 2, language.Exact, "Titel"},
+		{"de-DE;q=0.5, fr-FR", 1, language.Exact, "Titre"},
+		{"de-DE;q=0, fr-FR;q=0.1", 1, language.Exact, "Titre"},
+		{"es;q=0.9, de-DE;q=0.1", 2, language.Exact, "Titel"},
+		{"zh-CN", 0, language.No, "Title"},
+	}
+
+	for _, tt := range tests {
+		_, index, conf := i18N.MatchAcceptLanguage(tt.header)
+		if conf > language.Low && index != tt.expectedIndex {
+			t.Fatalf("[%s] expected index %d but got %d", tt.header, tt.expectedIndex, index)
+		}
+		if conf != tt.expectedConf {
+			t.Fatalf("[%s] expected confidence %s but got %s", tt.header, tt.expectedConf, conf)
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", tt.header)
+		if got := i18N.GetMessage(r, "title"); got != tt.expectedMessage {
+			t.Fatalf("[%s] expected message %s but got %s", tt.header, tt.expectedMessage, got)
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Header = "X-Locale"
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Locale", "el-GR")
+	r.Header.Set("Accept-Language", "en-US")
+	if got, expected := i18N.GetMessage(r, "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// not matched, fallback to the Accept-Language.
+	r.Header.Set("X-Locale", "zh-CN")
+	r.Header.Set("Accept-Language", "el-GR")
+	if got, expected := i18N.GetMessage(r, "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestSources(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Cookie = "lang"
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "lang", Value: "en-US"})
+	r.Header.Set("Accept-Language", "el-GR")
+
+	if got, expected := i18N.GetMessage(r, "title"), "Title"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	i18N.Sources = []LanguageSource{SourceAcceptLanguage, SourceCookie}
+	if got, expected := i18N.GetMessage(r, "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// unknown sources are skipped.
+	i18N.Sources = []LanguageSource{0, 42, SourceAcceptLanguage}
+	if got, expected := i18N.GetMessage(r, "title"), "Τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestGetLocaleAndPersist(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.URLParameter = "lang"
+	i18N.Cookie = "lang"
+
+	tests := []struct {
+		target            string
+		cookie            string
+		setCookieOnDetect bool
+		expectedCookie    string
+	}{
+		{"/?lang=el-GR", "", true, "el-GR"},
+		{"/?lang=en-US", "el-GR", true, "en-US"},
+		{"/?lang=el-GR", "el-GR", true, ""},
+		{"/", "el-GR", true, ""},
+		{"/?lang=el-GR", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		i18N.SetCookieOnDetect = tt.setCookieOnDetect
+
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+		}
+		w := httptest.NewRecorder()
+
+		if loc := i18N.GetLocaleAndPersist(w, r); loc == nil {
+			t.Fatalf("[%s] expected a locale", tt.target)
+		}
+
+		got := ""
+		if cookies := w.Result().Cookies(); len(cookies) > 0 {
+			got = cookies[0].Value
+		}
+
+		if got != tt.expectedCookie {
+			t.Fatalf("[%s] expected cookie %q but got %q", tt.target, tt.expectedCookie, got)
+		}
+	}
+}
+
+func TestSplitSubdomain(t *testing.T) {
+	tests := []struct {
+		hostport, subdomain, host string
+	}{
+		{"el.example.com", "el", "example.com"},
+		{"el.example.com:8080", "el", "example.com:8080"},
+		{"example.com:8080", "example", "com:8080"},
+		{"localhost:8080", "", "localhost:8080"},
+		{"localhost", "", "localhost"},
+		{"127.0.0.1:8080", "", "127.0.0.1:8080"},
+		{"127.0.0.1", "", "127.0.0.1"},
+		{"[::1]:8080", "", "[::1]:8080"},
+		{"[::1]", "", "[::1]"},
+		{"::1", "", "::1"},
+		{"[2001:db8::1]:443", "", "[2001:db8::1]:443"},
+	}
+
+	for _, tt := range tests {
+		subdomain, host := splitSubdomain(tt.hostport)
+		if subdomain != tt.subdomain || host != tt.host {
+			t.Fatalf("[%s] expected %q and %q but got %q and %q", tt.hostport, tt.subdomain, tt.host, subdomain, host)
+		}
+	}
+
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Subdomain = true
+
+	var host, msg string
+	i18N.Router(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		msg = i18N.GetMessage(r, "title")
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://el.example.com:8080/", nil))
+
+	if host != "example.com:8080" || msg != "Τίτλος" {
+		t.Fatalf("expected the example.com:8080 host and the el-GR message but got %s and %s", host, msg)
+	}
+}
+
+func TestURLParameterList(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.URLParameter = "lang"
+	i18N.Cookie = "lang"
+
+	tests := []struct {
+		target   string
+		cookie   string
+		expected string
+	}{
+		{"/?lang=el-GR", "", "el-GR"},
+		{"/?lang=el_gr", "", "el-GR"},
+		{"/?lang=el-GR,en-US", "", "el-GR"},
+		{"/?lang=en-US,el-GR", "", "en-US"},
+		{"/?lang=fr-FR,%20el-GR,en-US", "", "el-GR"},
+		{"/?lang=fr-FR,de-DE", "el-GR", "el-GR"},
+		{"/", "fr-FR,el-GR", "el-GR"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+		}
+
+		if got := i18N.GetLocale(r).Language(); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.target, tt.expected, got)
+		}
+	}
+}
+
+func TestTrustForwardedHeaders(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Subdomain = true
+
+	tests := []struct {
+		trust    bool
+		header   string
+		value    string
+		expected string
+	}{
+		{false, "X-Forwarded-Host", "el.example.com", "Title"},
+		{true, "X-Forwarded-Host", "el.example.com", "Τίτλος"},
+		{true, "X-Forwarded-Host", "el.example.com, proxy.internal", "Τίτλος"},
+		{true, "Forwarded", `for=192.0.2.60;host="el.example.com";proto=https, for=198.51.100.17`, "Τίτλος"},
+		{true, "Forwarded", "for=192.0.2.60;proto=https", "Title"},
+	}
+
+	for i, tt := range tests {
+		i18N.TrustForwardedHeaders = tt.trust
+
+		r := httptest.NewRequest("GET", "http://internal-host/", nil)
+		r.Header.Set(tt.header, tt.value)
+
+		if got := i18N.GetMessage(r, "title"); got != tt.expected {
+			t.Fatalf("[%d] expected %s but got %s", i, tt.expected, got)
+		}
+	}
+
+	// the domain of the language cookie.
+	i18N.Subdomain = false
+	i18N.TrustForwardedHeaders = true
+	i18N.Cookie = "lang"
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://internal-host/el-GR/", nil)
+	r.Header.Set("X-Forwarded-Host", "www.example.com")
+	i18N.Router(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(w, r)
+
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Domain != "example.com" {
+		t.Fatalf("expected a cookie of the example.com domain but got %v", cookies)
+	}
+}
+
+func TestCookieOptions(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Cookie = "lang"
+
+	serve := func() *http.Cookie {
+		w := httptest.NewRecorder()
+		i18N.Router(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).
+			ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/el-GR/", nil))
+
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("expected one cookie but got %d", len(cookies))
+		}
+
+		return cookies[0]
+	}
+
+	cookie := serve()
+	if cookie.Domain != "example.com" || cookie.SameSite != http.SameSiteLaxMode || cookie.Secure || cookie.Path != "" {
+		t.Fatalf("unexpected default cookie: %s", cookie)
+	}
+
+	i18N.CookieOptions = CookieOptions{
+		Path:     "/",
+		MaxAge:   3600,
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	}
+
+	cookie = serve()
+	if cookie.Value != "el-GR" || cookie.Path != "/" || cookie.MaxAge != 3600 || !cookie.Secure ||
+		!cookie.HttpOnly || cookie.SameSite != http.SameSiteNoneMode {
+		t.Fatalf("unexpected cookie: %s", cookie)
+	}
+}
+
+func TestLocaleFromRequest(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Cookie = "lang"
+
+	if _, ok := LocaleFromRequest(httptest.NewRequest("GET", "/", nil)); ok {
+		t.Fatalf("expected no locale outside of the Middleware and the Router")
+	}
+
+	tests := []struct {
+		wrapper  func(http.Handler) http.Handler
+		target   string
+		cookie   string
+		expected string
+	}{
+		{i18N.Router, "/el-gr/some-path", "en-US", "el-GR"}, // the path wins over a stale cookie.
+		{i18N.Router, "/some-path", "el-GR", "el-GR"},
+		{i18N.Middleware, "/some-path", "el-GR", "el-GR"},
+		{i18N.Middleware, "/some-path", "", "en-US"},
+	}
+
+	for _, tt := range tests {
+		var lang, msg string
+		handler := tt.wrapper(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			loc, ok := LocaleFromRequest(r)
+			if !ok {
+				t.Fatalf("[%s] expected a locale", tt.target)
+			}
+
+			lang = loc.Language()
+			msg = i18N.GetMessage(r, "title")
+		}))
+
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "lang", Value: tt.cookie})
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		if lang != tt.expected {
+			t.Fatalf("[%s] expected language %s but got %s", tt.target, tt.expected, lang)
+		}
+
+		if expected := i18N.Tr(tt.expected, "title"); msg != expected {
+			t.Fatalf("[%s] expected message %s but got %s", tt.target, expected, msg)
+		}
+	}
+}
+
+func TestLocaleCount(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"items":    Map{"one": "{count} item", "other": "{count} items"},
+			"messages": Map{"one": "%d message", "other": "%d messages"},
+			"dogs":     Map{"one": "{{.count}} dog", "other": "{{.count}} dogs"},
+		},
+		"el-GR": Map{
+			"items": Map{"one": "{count} αντικείμενο", "other": "{count} αντικείμενα"},
+		},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		n        int
+		expected string
+	}{
+		{"en-US", "items", 1, "1 item"},
+		{"en-US", "items", 1000, "1,000 items"},
+		{"en-US", "messages", 1, "1 message"},
+		{"en-US", "messages", 1000, "1,000 messages"},
+		{"en-US", "dogs", 1, "1 dog"},
+		{"en-US", "dogs", 1000, "1,000 dogs"},
+		{"el-GR", "items", 1000, "1.000 αντικείμενα"},
+		{"el-GR", "missing", 1, ""},
+	}
+
+	for _, tt := range tests {
+		loc := i18N.GetLocaleByTag(language.MustParse(tt.lang))
+		if got := loc.Count(tt.key, tt.n); got != tt.expected {
+			t.Fatalf("[%s:%s:%d] expected %q but got %q", tt.lang, tt.key, tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestEscapedDotKey(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"files": Map{
+				"config.yaml":  "The configuration file",
+				`readme\.md`:   "The readme file",
+				"plain":        "Plain",
+				"settings.ini": Map{"one": "%d setting", "other": "%d settings"},
+			},
+		},
+	}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{`files.config\.yaml`, nil, "The configuration file"},
+		{"files.config.yaml", nil, "The configuration file"},
+		{`files.readme\.md`, nil, "The readme file"},
+		{"files.readme.md", nil, "The readme file"},
+		{`files.settings\.ini`, []interface{}{PluralCount(2)}, "2 settings"},
+		{"files.plain", nil, "Plain"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr("en-US", tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s] expected %q but got %q", tt.key, tt.expected, got)
+		}
+	}
+
+	if !i18N.Exists("en-US", `files.config\.yaml`) {
+		t.Fatalf("expected the escaped key to exist")
+	}
+
+	if got, expected := i18N.TrPlural("en-US", `files.settings\.ini`, 1), "1 setting"; got != expected {
+		t.Fatalf("expected %q but got %q", expected, got)
+	}
+}
+
+func TestLocaleStruct(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"form": Map{"email": "Email address", "password": "Password", "name": "Name"}},
+		"el-GR": Map{"form": Map{"email": "Διεύθυνση email", "password": "Κωδικός"}},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Base struct {
+		Name string `i18n:"form.name"`
+	}
+
+	type SignupForm struct {
+		Base
+		Email    string `i18n:"form.email"`
+		Password string `i18n:"form.password"`
+		Token    string `i18n:"-"`
+		Remember bool
+	}
+
+	i18N.URLParameter = "lang"
+	got := i18N.GetLocale(httptest.NewRequest(http.MethodGet, "/?lang=el-GR", nil)).Struct(&SignupForm{})
+	expected := map[string]string{
+		"Name":     "",
+		"Email":    "Διεύθυνση email",
+		"Password": "Κωδικός",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v but got %v", expected, got)
+	}
+
+	if got := i18N.DefaultLocale().Struct("not a struct"); got != nil {
+		t.Fatalf("expected nil labels of a non-struct value but got %v", got)
+	}
+}
+
+func TestRouterUnderscore(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title"},
+		"zh-CN": Map{"title": "标题"},
+	}), "en-US", "zh-CN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.Subdomain = true
+
+	tests := []string{
+		"http://mydomain.com/zh-cn/some-path",
+		"http://mydomain.com/zh_cn/some-path",
+		"http://zh-cn.mydomain.com/some-path",
+		"http://zh_cn.mydomain.com/some-path",
+	}
+
+	for _, target := range tests {
+		var path, msg string
+		handler := i18N.Router(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			msg = i18N.GetMessage(r, "title")
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+
+		if expected := "/some-path"; path != expected {
+			t.Fatalf("[%s] expected path %s but got %s", target, expected, path)
+		}
+		if expected := "标题"; msg != expected {
+			t.Fatalf("[%s] expected message %s but got %s", target, expected, msg)
+		}
+	}
+}
+
+func TestRedirectRouter(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.RedirectSkipper = func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, "/assets/")
+	}
+
+	handler := i18N.RedirectRouter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path + ":" + i18N.GetMessage(r, "title")))
+	}))
+
+	tests := []struct {
+		target           string
+		acceptLanguage   string
+		expectedCode     int
+		expectedLocation string
+		expectedBody     string
+	}{
+		{"/some-path?foo=bar", "el-GR", http.StatusFound, "/el-gr/some-path?foo=bar", ""},
+		{"/", "", http.StatusFound, "/en-us/", ""},
+		{"/el-gr/some-path", "en-US", http.StatusOK, "", "/some-path:Τίτλος"},
+		{"/assets/app.js", "el-GR", http.StatusOK, "", "/assets/app.js:Τίτλος"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		if tt.acceptLanguage != "" {
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tt.expectedCode {
+			t.Fatalf("[%s] expected status code %d but got %d", tt.target, tt.expectedCode, w.Code)
+		}
+		if got := w.Header().Get("Location"); got != tt.expectedLocation {
+			t.Fatalf("[%s] expected location %s but got %s", tt.target, tt.expectedLocation, got)
+		}
+		if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+			t.Fatalf("[%s] expected body %s but got %s", tt.target, tt.expectedBody, w.Body.String())
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i18N.ContextKey = "lang"
+
+	var (
+		loc      *Locale
+		lang     interface{}
+		msg      string
+		override string
+	)
+	handler := i18N.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loc = LocaleFromContext(r.Context())
+		lang = r.Context().Value("lang")
+		r.Header.Set("Accept-Language", "en-US") // detection should not run again.
+		msg = i18N.GetMessage(r, "title")
+		override = i18N.GetMessage(i18N.WithLanguage(r, "en-US"), "title")
+	}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "el-GR")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if loc == nil || loc.Language() != "el-GR" {
+		t.Fatalf("expected el-GR locale but got %v", loc)
+	}
+	if lang != "el-GR" {
+		t.Fatalf("expected el-GR context value but got %v", lang)
+	}
+	if expected := "Τίτλος"; msg != expected {
+		t.Fatalf("expected %s but got %s", expected, msg)
+	}
+	if expected := "Title"; override != expected {
+		t.Fatalf("expected %s but got %s", expected, override)
+	}
+
+	if got := LocaleFromContext(context.Background()); got != nil {
+		t.Fatalf("expected nil locale but got %v", got)
+	}
+}
+
+func TestMatchCache(t *testing.T) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "el-GR,en;q=0.5")
+	for n := 0; n < 2; n++ {
+		if got, expected := i18N.GetMessage(r, "title"), "Τίτλος"; got != expected {
+			t.Fatalf("expected %s but got %s", expected, got)
+		}
+	}
+
+	if !i18N.SetDefault("el-GR") {
+		t.Fatalf("expected default language to be changed")
+	}
+
+	// the language indexes are kept.
+	if _, index, _ := i18N.MatchAcceptLanguage("el-GR,en;q=0.5"); index != 1 {
+		t.Fatalf("expected index 1 but got %d", index)
+	}
+
+	cache := newMatchCache(2)
+	for _, input := range []string{"en", "el", "en", "zh"} {
+		cache.set(matchCacheKey{input: input}, matchResult{})
+	}
+
+	if _, ok := cache.get(matchCacheKey{input: "el"}); ok {
+		t.Fatalf("expected the least recently used input to be evicted")
+	}
+	if n := cache.order.Len(); n != 2 {
+		t.Fatalf("expected 2 cached inputs but got %d", n)
+	}
+}
+
+func BenchmarkGetLocaleAcceptLanguage(b *testing.B) {
+	i18N, err := New(Glob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "fr-CH, fr;q=0.9, el-GR;q=0.8, en;q=0.7, *;q=0.5")
+
+	for _, disable := range []bool{true, false} {
+		name := "cache"
+		if disable {
+			name = "no-cache"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			i18N.DisableMatchCache = disable
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				if loc := i18N.GetLocale(r); loc == nil {
+					b.Fatal("expected a locale")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkTemplateMessage compares the load, where the template messages
+// are parsed once, with the per-call render of the precompiled templates.
+func BenchmarkTemplateMessage(b *testing.B) {
+	langMap := LangMap{
+		"en-US": Map{"hi": "Hi {{.Name}}, you have {{.Count}} new messages"},
+	}
+	data := map[string]interface{}{"Name": "kataras", "Count": 3}
+	expected := "Hi kataras, you have 3 new messages"
+
+	b.Run("cold", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			i18N, err := New(KV(langMap), "en-US")
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if got := i18N.Tr("en-US", "hi", data); got != expected {
+				b.Fatalf("expected %s but got %s", expected, got)
+			}
+		}
+	})
+
+	b.Run("warm", func(b *testing.B) {
+		i18N, err := New(KV(langMap), "en-US")
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if got := i18N.Tr("en-US", "hi", data); got != expected {
+				b.Fatalf("expected %s but got %s", expected, got)
+			}
+		}
+	})
+}
+
+func TestLocaleMarshalJSON(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"title": "Title",
+			"nav": Map{
+				"home": "Home",
+				"more": Map{"what": "What"},
+			},
+			"hi": "Hi {{.Name}}",
+			"items": Map{
+				"one":   "%d item",
+				"other": "%d items",
+			},
+			"dogs": Map{
+				"one":   "{{.PluralCount}} dog",
+				"other": "{{.PluralCount}} dogs",
+			},
+		},
+	}), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := i18N.GetLocale(httptest.NewRequest("GET", "/", nil))
+	if got, expected := loc.String(), "en-US (6 messages)"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	b, err := json.Marshal(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"lang": "en-US",
+		"messages": map[string]interface{}{
+			"title": "Title",
+			"nav": map[string]interface{}{
+				"home": "Home",
+				"more": map[string]interface{}{"what": "What"},
+			},
+			"hi": "Hi {{.Name}}",
+			"items": map[string]interface{}{
+				"one":   "%d item",
+				"other": "%d items",
+			},
+			"dogs": map[string]interface{}{
+				"one":   "{{.PluralCount}} dog",
+				"other": "{{.PluralCount}} dogs",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v but got %v (%s)", expected, got, b)
+	}
+}
+
+func TestJSONHandler(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"title": "Title"},
+		"el-GR": Map{"title": "Τίτλος"},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := i18N.JSONHandler()
+
+	tests := []struct {
+		target         string
+		acceptLanguage string
+		expectedCode   int
+		expectedBody   string
+	}{
+		{"/i18n", "el-GR", http.StatusOK, `{"lang":"el-GR","messages":{"title":"Τίτλος"}}`},
+		{"/i18n?lang=en-US", "el-GR", http.StatusOK, `{"lang":"en-US","messages":{"title":"Title"}}`},
+		{"/i18n?lang=zh-CN", "", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", tt.target, nil)
+		r.Header.Set("Accept-Language", tt.acceptLanguage)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != tt.expectedCode {
+			t.Fatalf("[%s] expected status code %d but got %d", tt.target, tt.expectedCode, w.Code)
+		}
+
+		if tt.expectedCode != http.StatusOK {
+			continue
+		}
+
+		if got := w.Body.String(); got != tt.expectedBody {
+			t.Fatalf("[%s] expected body %s but got %s", tt.target, tt.expectedBody, got)
+		}
+		if got, expected := w.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != expected {
+			t.Fatalf("[%s] expected content type %s but got %s", tt.target, expected, got)
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" || w.Header().Get("Last-Modified") == "" {
+			t.Fatalf("[%s] expected ETag and Last-Modified headers", tt.target)
+		}
+
+		r = httptest.NewRequest("GET", tt.target, nil)
+		r.Header.Set("Accept-Language", tt.acceptLanguage)
+		r.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotModified {
+			t.Fatalf("[%s] expected status code %d but got %d", tt.target, http.StatusNotModified, w.Code)
+		}
+	}
+}
+
+// BenchmarkPlainMessage renders a static message, which is returned as it is,
+// without a template or a printf-style formatting.
+func BenchmarkPlainMessage(b *testing.B) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"nav": Map{"home": "Home"}},
+	}), "en-US")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if got := i18N.Tr("en", "nav.home"); got != "Home" {
+			b.Fatalf("expected Home but got %s", got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"text/template"
 
@@ -25,6 +26,7 @@ type MessageFunc func(langInput, langMatched, key string, args ...interface{}) s
 // Catalog holds the locales and the variables message storage.
 type Catalog struct {
 	builder *catalog.Builder
+	options Options
 	Locales []*Locale
 }
 
@@ -34,14 +36,158 @@ type Options struct {
 	Left string
 	// Right delimeter for template messages.
 	Right string
+	// Delims are the template delimiters per language code, e.g. "zh-CN" or "zh",
+	// the languages which are not listed use the Left and Right ones.
+	Delims map[string]Delims
 	// Enable strict mode.
 	Strict bool
 	// Optional functions for template messages per locale.
+	// They override the builtin ones of the same name, e.g. "upper" or "title".
 	Funcs func(*Locale) template.FuncMap
 	// Optional function to be called when no message was found.
 	DefaultMessageFunc MessageFunc
 	// Customize the overall behavior of the plurazation feature.
 	PluralFormDecoder PluralFormDecoder
+	// PluralFunc overrides the CLDR plural rules of the languages, it returns the plural
+	// category of the "count", e.g. "one" or "other", which selects among the "zero", "one",
+	// "two", "few", "many" and "other" forms of a plural message. The "other" form is selected
+	// when no other form matches. The exact forms, e.g. "=5", are not affected.
+	// Defaults to nil, the CLDR plural rules of the language.
+	PluralFunc func(tag language.Tag, count interface{}) string
+	// PluralCountKey is the key of the template data maps which selects
+	// the plural form of a message, defaults to "PluralCount".
+	// When the data map contains it, it always drives the plural selection.
+	PluralCountKey string
+	// PositionalPluralCount enables the plural form selection by a leading
+	// integer argument, e.g. Tr("en-US", "files", 2), for compatibility.
+	// Defaults to false, the plain integers are template or format data only
+	// and the plural form is selected by a `PluralCount` argument,
+	// the PluralCountKey of the data or a PluralCounter,
+	// so an integer template data is never mistaken for the plural count.
+	// Only the integer types are accepted as a positional count,
+	// a numeric string or a float argument is data.
+	PositionalPluralCount bool
+	// MissingKey controls the template messages on a missing key of their map data, e.g. {{.Naem}},
+	// it is the "missingkey" option of the text/template package:
+	// "default" (or empty) renders "<no value>", "zero" renders the zero value
+	// and "error" fails the rendering, so the `TrError` reports it.
+	// Defaults to empty, the lenient behavior.
+	MissingKey string
+	// StrictTemplates reports the values which contain the Left or Right delimiter
+	// but are not valid templates, e.g. "Hi {{.Name}", as load errors.
+	// Defaults to false, those values are loaded as plain text messages.
+	StrictTemplates bool
+	// LangFromDir forces the file loaders to read the language of a locale file
+	// only from its immediate parent directory, e.g. "en-US" for the "./locales/en-US/buttons.en.yml",
+	// so the language-like words of the file names are ignored.
+	// Defaults to false, the last path segment which is a language code wins.
+	LangFromDir bool
+	// OnlyRegisteredLanguages forces the file loaders to recognize only the languages
+	// passed to the `New` function, so a word of a file path which happens to be
+	// a language code, e.g. "no" (Norwegian) or "id" (Indonesian) of "./locales/el-GR/id.yml",
+	// is skipped and the next language-like word is tried, the "el-GR" on that example.
+	// It has no effect when the languages are not passed to the `New` function.
+	OnlyRegisteredLanguages bool
+	// Exclude are glob patterns of the file names which the file loaders skip,
+	// e.g. "*.schema.yaml" or "locales/drafts/*". A pattern without a slash
+	// matches the base name of a file, otherwise its whole (slash-separated) name.
+	// The excluded files do not affect the language detection either.
+	Exclude []string
+	// OnDuplicateKey is the action to take when a key is defined by more than one
+	// locale file of the same language. Defaults to DuplicateKeyOverride, the last file wins.
+	// A key of an escaped dot, e.g. `config\.yaml`, which is defined by nested maps too,
+	// e.g. config: {yaml: "..."}, is a duplicate as well.
+	OnDuplicateKey DuplicateKey
+	// KeyResolution decides which message of a dotted key is stored when a locale
+	// defines it both as a literal flat key, e.g. "nav.home": "Home",
+	// and as a nested one, e.g. nav: {home: "Home"}, so the `Locale.GetMessage`
+	// resolves it the same way on every load. Defaults to KeyResolutionFlat, the flat key wins.
+	KeyResolution KeyResolution
+	// CSVDelimiter is the field delimiter of the CSV loader,
+	// defaults to ',' or '\t' for the ".tsv" files.
+	CSVDelimiter rune
+	// Keys starting with any of these prefixes, e.g. "legal.",
+	// do not fallback to the default language when not found.
+	NoFallbackPrefixes []string
+}
+
+// ErrDuplicateKey is reported when a key is defined more than once
+// for the same language and the `DuplicateKeyError` is set.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// DuplicateKey describes what to do when a translation key is defined more than once
+// for the same language, e.g. by different locale files or loaders.
+type DuplicateKey uint8
+
+const (
+	// DuplicateKeyOverride silently overrides the previous value with the last one.
+	DuplicateKeyOverride DuplicateKey = iota
+	// DuplicateKeyWarn overrides the previous value with the last one and logs a warning.
+	DuplicateKeyWarn
+	// DuplicateKeyError fails the loading.
+	DuplicateKeyError
+)
+
+// KeyResolution describes which message of a dotted key wins when a locale defines it
+// both as a literal flat key and as a nested one, see `Options.KeyResolution`.
+type KeyResolution uint8
+
+const (
+	// KeyResolutionFlat stores the message of the literal flat key, e.g. "nav.home": "Home",
+	// over the nested one, e.g. nav: {home: "Home"}. The key of the most literal dots wins.
+	KeyResolutionFlat KeyResolution = iota
+	// KeyResolutionNested stores the message of the nested key, e.g. nav: {home: "Home"},
+	// over the literal flat one, e.g. "nav.home": "Home". The key of the most nested levels wins.
+	KeyResolutionNested
+)
+
+// Delims are the Left and Right template delimiters of a language, see `Options.Delims`.
+type Delims struct {
+	Left  string
+	Right string
+}
+
+// localeOptions returns the options of the "tag" language,
+// with its own delimiters if any, the exact language ones
+// take precedence over the base language ones.
+func localeOptions(tag language.Tag, opts Options) Options {
+	if len(opts.Delims) == 0 {
+		return opts
+	}
+
+	base, _ := tag.Base()
+
+	var (
+		delims Delims
+		found  bool
+	)
+
+	for lang, d := range opts.Delims {
+		t, err := language.Parse(lang)
+		if err != nil {
+			continue
+		}
+
+		if t == tag {
+			delims, found = d, true
+			break
+		}
+
+		if t == language.Make(base.String()) {
+			delims, found = d, true // keep looking for the exact one.
+		}
+	}
+
+	if found {
+		if delims.Left != "" {
+			opts.Left = delims.Left
+		}
+		if delims.Right != "" {
+			opts.Right = delims.Right
+		}
+	}
+
+	return opts
 }
 
 // NewCatalog returns a new Catalog based on the registered languages and the loader options.
@@ -50,6 +196,12 @@ func NewCatalog(languages []language.Tag, opts Options) (*Catalog, error) { // o
 		return nil, fmt.Errorf("catalog: empty languages")
 	}
 
+	switch opts.MissingKey {
+	case "", "default", "zero", "error":
+	default:
+		return nil, fmt.Errorf("catalog: invalid missing key option: %q", opts.MissingKey)
+	}
+
 	if opts.Left == "" {
 		opts.Left = "{{"
 	}
@@ -62,31 +214,72 @@ func NewCatalog(languages []language.Tag, opts Options) (*Catalog, error) { // o
 		opts.PluralFormDecoder = DefaultPluralFormDecoder
 	}
 
-	builder := catalog.NewBuilder(catalog.Fallback(languages[0]))
-
-	locales := make([]*Locale, 0, len(languages))
-	for idx, tag := range languages {
-		locale := &Locale{
-			tag:      tag,
-			index:    idx,
-			ID:       tag.String(),
-			Options:  opts,
-			Printer:  message.NewPrinter(tag, message.Catalog(builder)),
-			Messages: make(map[string]Renderer),
-		}
-		locale.FuncMap = getFuncs(locale)
-
-		locales = append(locales, locale)
+	if opts.PluralCountKey == "" {
+		opts.PluralCountKey = PluralCountKey
 	}
 
+	builder := catalog.NewBuilder(catalog.Fallback(languages[0]))
+
 	c := &Catalog{
 		builder: builder,
-		Locales: locales,
+		options: opts,
+		Locales: make([]*Locale, 0, len(languages)),
+	}
+
+	for _, tag := range languages {
+		c.AddLocale(tag)
 	}
 
 	return c, nil
 }
 
+// AddLocale adds a new, empty, Locale of the "tag" language
+// with the next language index and returns it.
+// Callers should protect with mutex if called at serve-time.
+func (c *Catalog) AddLocale(tag language.Tag) *Locale {
+	locale := &Locale{
+		tag:      tag,
+		index:    len(c.Locales),
+		ID:       tag.String(),
+		Options:  localeOptions(tag, c.options),
+		Printer:  message.NewPrinter(tag, message.Catalog(c.builder)),
+		Messages: make(map[string]Renderer),
+	}
+	locale.catalog = c
+	locale.FuncMap = getFuncs(locale)
+
+	c.Locales = append(c.Locales, locale)
+	return locale
+}
+
+// Clone returns a copy of the Catalog, of the same languages and options, which more messages
+// can be stored to without affecting the readers of this one, see `I18n.SetMessages`.
+// The copied messages keep their own locale, like the merged ones of the `Chain` loader.
+// If "copyMessages" is not nil then only the messages of the locales which it reports true
+// are copied, e.g. the loaded ones of a lazy localizer.
+func (c *Catalog) Clone(copyMessages func(loc *Locale) bool) *Catalog {
+	clone := &Catalog{
+		builder: catalog.NewBuilder(catalog.Fallback(c.Locales[0].tag)),
+		options: c.options,
+		Locales: make([]*Locale, 0, len(c.Locales)),
+	}
+
+	for _, loc := range c.Locales {
+		locale := clone.AddLocale(loc.tag)
+		if copyMessages != nil && !copyMessages(loc) {
+			continue
+		}
+
+		locale.Vars = loc.Vars
+		for key, renderer := range loc.Messages {
+			locale.Messages[key] = renderer
+		}
+		CopyMeta(locale, loc, "")
+	}
+
+	return clone
+}
+
 // Set sets a simple translation message.
 func (c *Catalog) Set(tag language.Tag, key string, msgs ...catalog.Message) error {
 	// fmt.Printf("Catalog.Set[%s] %s:\n", tag.String(), key)
@@ -107,27 +300,6 @@ func (c *Catalog) Store(langIndex int, kv Map) error {
 
 /* Localizer interface. */
 
-// SetDefault changes the default language based on the "index".
-// See `I18n#SetDefault` method for more.
-func (c *Catalog) SetDefault(index int) bool {
-	if index < 0 {
-		index = 0
-	}
-
-	if maxIdx := len(c.Locales) - 1; index > maxIdx {
-		return false
-	}
-
-	// callers should protect with mutex if called at serve-time.
-	loc := c.Locales[index]
-	loc.index = 0
-	f := c.Locales[0]
-	c.Locales[0] = loc
-	f.index = index
-	c.Locales[index] = f
-	return true
-}
-
 // GetLocale returns a valid `Locale` based on the "index".
 func (c *Catalog) GetLocale(index int) *Locale {
 	return c.getLocale(index)
@@ -0,0 +1,58 @@
+package internal
+
+// countPlaceholder is the name of the placeholder of the formatted count of the `Locale.Count`,
+// the `{count}` of the printf-style messages and the `{{.count}}` of the template ones.
+const countPlaceholder = "count"
+
+// Count returns the plural form of the "key" message which matches the "n" count,
+// with the "n" formatted by the rules of the locale's language, e.g. "1,000 items" on English:
+//
+//	items:
+//	  one: "{count} item"
+//	  other: "{count} items"
+//
+// The count is the `{count}` named placeholder, the `{{.count}}` of a template message
+// or the first format verb, e.g. "%d items", of a printf-style one.
+func (loc *Locale) Count(key string, n int) string {
+	msg, ok := loc.Messages[UnescapeKey(key)]
+	if !ok {
+		return loc.GetPluralMessage(key, n)
+	}
+
+	r := msg
+	if m, ok := msg.(*Message); ok && m.Plural {
+		r = nil
+		for _, plural := range m.Plurals {
+			if plural.Form.MatchPlural(n) {
+				r = plural.Renderer
+				break
+			}
+		}
+
+		if r == nil {
+			return loc.GetPluralMessage(key, n) // the error of no plural form.
+		}
+	}
+
+	result, err := loc.renderCount(r, n)
+	if err != nil {
+		return err.Error()
+	}
+
+	return result
+}
+
+func (loc *Locale) renderCount(r Renderer, n int) (string, error) {
+	data := map[string]interface{}{countPlaceholder: loc.formatNumber(n)}
+
+	switch v := r.(type) {
+	case *Template:
+		return v.Render(data)
+	case *Message:
+		if v.named {
+			return v.Render(n, data)
+		}
+	}
+
+	return renderWithCount(r, reference{}, n, nil)
+}
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The date styles of the `Locale.FormatDate`.
+const (
+	dateShort = "short"
+	dateLong  = "long"
+)
+
+// dateFormat holds the date patterns and the month names of a language.
+// The patterns are a subset of the CLDR ones:
+// "d", "dd" for the day, "M", "MM", "MMM", "MMMM" for the month,
+// "yy", "y" for the year and any 'quoted' literal text.
+type dateFormat struct {
+	short, medium, long string
+	months, shortMonths [12]string
+}
+
+// dateFormats are the date formats by base language.
+var dateFormats = map[string]*dateFormat{
+	"en": {
+		short:       "M/d/yy",
+		medium:      "MMM d, y",
+		long:        "MMMM d, y",
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	},
+	"el": {
+		short:       "d/M/yy",
+		medium:      "d MMM y",
+		long:        "d MMMM y",
+		months:      [12]string{"Ιανουαρίου", "Φεβρουαρίου", "Μαρτίου", "Απριλίου", "Μαΐου", "Ιουνίου", "Ιουλίου", "Αυγούστου", "Σεπτεμβρίου", "Οκτωβρίου", "Νοεμβρίου", "Δεκεμβρίου"},
+		shortMonths: [12]string{"Ιαν", "Φεβ", "Μαρ", "Απρ", "Μαΐ", "Ιουν", "Ιουλ", "Αυγ", "Σεπ", "Οκτ", "Νοε", "Δεκ"},
+	},
+	"de": {
+		short:       "dd.MM.yy",
+		medium:      "dd.MM.y",
+		long:        "d. MMMM y",
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+	},
+	"fr": {
+		short:       "dd/MM/y",
+		medium:      "d MMM y",
+		long:        "d MMMM y",
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+	},
+	"es": {
+		short:       "d/M/yy",
+		medium:      "d MMM y",
+		long:        "d 'de' MMMM 'de' y",
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+	},
+}
+
+// FormatDate returns the date of "t" formatted by the rules of the locale's language
+// and the "style", which can be "short", "medium" or "long",
+// e.g. "1/5/24", "Jan 5, 2024" and "January 5, 2024" on English
+// and "5/1/24", "5 Ιαν 2024" and "5 Ιανουαρίου 2024" on Greek.
+// An unknown style is the "medium" one.
+//
+// The English, Greek, German, French and Spanish languages are supported,
+// the rest are formatted as "2024-01-05", regardless of the style.
+func (loc *Locale) FormatDate(t time.Time, style string) string {
+	base, _ := loc.tag.Base()
+	f, ok := dateFormats[base.String()]
+	if !ok {
+		return t.Format("2006-01-02")
+	}
+
+	var pattern string
+	switch style {
+	case dateShort:
+		pattern = f.short
+	case dateLong:
+		pattern = f.long
+	default:
+		pattern = f.medium
+	}
+
+	return f.format(t, pattern)
+}
+
+func (f *dateFormat) format(t time.Time, pattern string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(pattern); {
+		ch := pattern[i]
+
+		if ch == '\'' { // quoted literal text.
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end == -1 {
+				b.WriteString(pattern[i+1:])
+				break
+			}
+
+			b.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == ch {
+			n++
+		}
+
+		switch ch {
+		case 'd':
+			writeDatePart(&b, t.Day(), n)
+		case 'M':
+			switch n {
+			case 1, 2:
+				writeDatePart(&b, int(t.Month()), n)
+			case 3:
+				b.WriteString(f.shortMonths[t.Month()-1])
+			default:
+				b.WriteString(f.months[t.Month()-1])
+			}
+		case 'y':
+			if n == 2 {
+				writeDatePart(&b, t.Year()%100, 2)
+			} else {
+				b.WriteString(strconv.Itoa(t.Year()))
+			}
+		default:
+			b.WriteString(pattern[i : i+n])
+		}
+
+		i += n
+	}
+
+	return b.String()
+}
+
+// writeDatePart writes the "v" number padded with zeros to "width" digits.
+func writeDatePart(b *strings.Builder, v, width int) {
+	s := strconv.Itoa(v)
+	for i := len(s); i < width; i++ {
+		b.WriteByte('0')
+	}
+
+	b.WriteString(s)
+}
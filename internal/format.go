@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"golang.org/x/text/currency"
+	"golang.org/x/text/number"
+)
+
+// FormatNumber returns the "v" number formatted by the rules of the locale's language,
+// e.g. "1,234.56" on English and "1.234,56" on Greek.
+func (loc *Locale) FormatNumber(v float64) string {
+	return loc.formatNumber(v)
+}
+
+// FormatCurrency returns the "v" amount of the "code" ISO 4217 currency, e.g. "EUR",
+// formatted by the rules of the locale's language, e.g. "€ 1,234.56" on English
+// and "€ 1.234,56" on Greek.
+// If the "code" is not a valid currency then the number is followed by the "code".
+func (loc *Locale) FormatCurrency(v float64, code string) string {
+	return loc.formatCurrency(v, code)
+}
+
+// formatNumber and formatCurrency accept any number type, for the template functions.
+func (loc *Locale) formatNumber(v interface{}) string {
+	return loc.Printer.Sprint(number.Decimal(v))
+}
+
+func (loc *Locale) formatCurrency(v interface{}, code string) string {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return loc.formatNumber(v) + " " + code
+	}
+
+	return loc.Printer.Sprint(currency.Symbol(unit.Amount(v)))
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Gender is a message argument which selects the gender form of a message,
+// its "male", "female" or "other" sub-key.
+// It is a type of its own, so it is never confused with a plural count.
+type Gender uint8
+
+// The genders, their values match the "=1" and "=2" cases of the "${Gender}" variables.
+const (
+	// OtherGender selects the "other" form, it is the default one.
+	OtherGender Gender = iota
+	// Female selects the "female" form.
+	Female
+	// Male selects the "male" form.
+	Male
+)
+
+// String returns the sub-key of the gender form, e.g. "female".
+func (g Gender) String() string {
+	switch g {
+	case Female:
+		return "female"
+	case Male:
+		return "male"
+	default:
+		return "other"
+	}
+}
+
+// isGenderMap reports whether the "m" holds gender forms, e.g. {"male":"He","female":"She"}.
+func isGenderMap(m Map) bool {
+	hasGender := false
+	for k := range m {
+		switch k {
+		case "male", "female":
+			hasGender = true
+		case "other":
+		default:
+			return false
+		}
+	}
+
+	return hasGender
+}
+
+// setGender registers the gender forms of the "key",
+// each form is registered as a sub-key of it, e.g. "key.female".
+func (loc *Locale) setGender(c *Catalog, key string, forms Map) error {
+	var errs []error
+	for form, v := range forms {
+		formKey := key + "." + form
+
+		switch value := v.(type) {
+		case string:
+			if err := loc.setString(c, formKey, value, loc.Vars, nil); err != nil {
+				errs = append(errs, fmt.Errorf("%s:%s parse string: %w", loc.ID, formKey, err))
+			}
+		case Map:
+			if err := loc.setMap(c, formKey, value); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s:%s unexpected type of %T as value", loc.ID, formKey, value))
+		}
+	}
+
+	loc.Messages[key] = &genderMessage{locale: loc, key: key}
+	return errors.Join(errs...)
+}
+
+// genderMessage is a Renderer which renders the gender form,
+// selected by the `Gender` argument, or the "other" one if missing.
+type genderMessage struct {
+	locale *Locale
+	key    string
+}
+
+// Render completes the Renderer interface.
+// The `Gender` argument is removed from the arguments of the gender form.
+func (m *genderMessage) Render(args ...interface{}) (string, error) {
+	return m.renderRef(reference{}, args...)
+}
+
+func (m *genderMessage) renderRef(ref reference, args ...interface{}) (string, error) {
+	gender := OtherGender
+	for i, arg := range args {
+		if g, ok := arg.(Gender); ok {
+			gender = g
+			args = append(args[:i:i], args[i+1:]...)
+			break
+		}
+	}
+
+	form, ok := m.locale.Messages[m.key+"."+gender.String()]
+	if !ok {
+		if form, ok = m.locale.Messages[m.key+".other"]; !ok {
+			return "", fmt.Errorf("key: %q: no registered gender form for <%s>", m.key, gender)
+		}
+	}
+
+	if count, rest, ok := cutPluralCount(args); ok {
+		return renderPlural(form, ref, count, rest)
+	}
+
+	return renderRef(form, ref, args)
+}
@@ -1,7 +1,12 @@
 package internal
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"text/template"
 
 	"golang.org/x/text/language"
@@ -9,6 +14,9 @@ import (
 	"golang.org/x/text/message/catalog"
 )
 
+// ErrKeyNotFound is reported when a translation key was not found.
+var ErrKeyNotFound = errors.New("key not found")
+
 // Locale is the default Locale.
 // Created by Catalog.
 // One Locale maps to one registered and loaded language.
@@ -23,6 +31,7 @@ type Locale struct {
 	Options Options
 
 	// Fields set by Catalog.
+	catalog *Catalog
 	FuncMap template.FuncMap
 	Printer *message.Printer
 	//
@@ -30,13 +39,66 @@ type Locale struct {
 	// Fields set by this Load method.
 	Messages map[string]Renderer
 	Vars     []Var // shared per-locale variables.
+	meta     MetaMap
 }
 
 // Load sets the translation messages based on the Catalog's key values.
 func (loc *Locale) Load(c *Catalog, keyValues Map) error {
+	if err := loc.checkEscapedKeys(keyValues); err != nil {
+		return err
+	}
+
 	return loc.setMap(c, "", keyValues)
 }
 
+// checkEscapedKeys reports the keys of an escaped dot, e.g. `config\.yaml`, which are defined
+// by nested maps too, e.g. config: {yaml: "..."}, as duplicates, see `Options.OnDuplicateKey`.
+// Both of them are stored as the "config.yaml" key, the escaped one wins unless
+// the `Options.KeyResolution` is KeyResolutionNested.
+func (loc *Locale) checkEscapedKeys(keyValues Map) error {
+	escaped := make(map[string]bool)
+	var duplicates []string
+
+	var walk func(prefix string, isEscaped bool, m Map)
+	walk = func(prefix string, isEscaped bool, m Map) {
+		for k, v := range m {
+			if k == MetaKey {
+				continue
+			}
+
+			keyEscaped := isEscaped || strings.Contains(k, `\.`)
+			key := prefix + UnescapeKey(k)
+
+			if value, ok := v.(Map); ok {
+				walk(key+".", keyEscaped, value)
+				continue
+			}
+
+			if prev, ok := escaped[key]; ok && prev != keyEscaped {
+				duplicates = append(duplicates, key)
+				continue
+			}
+
+			escaped[key] = keyEscaped
+		}
+	}
+	walk("", false, keyValues)
+
+	sort.Strings(duplicates)
+
+	var errs []error
+	for _, key := range duplicates {
+		switch loc.Options.OnDuplicateKey {
+		case DuplicateKeyError:
+			errs = append(errs, fmt.Errorf("%s: %w: %s: defined by an escaped and a nested key", loc.ID, ErrDuplicateKey, key))
+		case DuplicateKeyWarn:
+			log.Printf("i18n: %s: key %q is defined by an escaped and a nested key", loc.ID, key)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func (loc *Locale) setMap(c *Catalog, key string, keyValues Map) error {
 	// unique locals or the shared ones.
 	isRoot := key == ""
@@ -44,43 +106,122 @@ func (loc *Locale) setMap(c *Catalog, key string, keyValues Map) error {
 	vars := getVars(loc, VarsKey, keyValues)
 	if isRoot {
 		loc.Vars = vars
+		if meta, ok := keyValues[MetaKey].(MetaMap); ok {
+			loc.setMeta(meta)
+		}
 	} else {
 		vars = removeVarsDuplicates(append(vars, loc.Vars...))
 	}
 
-	for k, v := range keyValues {
+	var errs []error // all of them, e.g. to report every broken template at once.
+	for _, k := range loc.sortedKeys(keyValues) {
+		v := keyValues[k]
+		if k == MetaKey {
+			continue
+		}
+
 		form, isPlural := loc.Options.PluralFormDecoder(loc, k)
 		if isPlural {
 			k = key
-		} else if !isRoot {
+		} else if k = UnescapeKey(k); !isRoot {
 			k = key + "." + k
 		}
 
 		switch value := v.(type) {
 		case string:
 			if err := loc.setString(c, k, value, vars, form); err != nil {
-				return fmt.Errorf("%s:%s parse string: %w", loc.ID, key, err)
+				errs = append(errs, fmt.Errorf("%s:%s parse string: %w", loc.ID, k, err))
 			}
 		case Map:
 			// fmt.Printf("%s is map\n", fullKey)
+			if description, msg, ok := cutGoI18nMessage(value); ok {
+				loc.setMeta(MetaMap{k: {Description: description}})
+
+				var err error
+				if text, ok := msg.(string); ok {
+					err = loc.setString(c, k, text, vars, nil)
+				} else {
+					err = loc.setMap(c, k, msg.(Map))
+				}
+
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s:%s parse message: %w", loc.ID, k, err))
+				}
+				continue
+			}
+
+			if isGenderMap(value) {
+				if err := loc.setGender(c, k, value); err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+
 			if err := loc.setMap(c, k, value); err != nil {
-				return fmt.Errorf("%s:%s parse map: %w", loc.ID, key, err)
+				errs = append(errs, err)
+			}
+		case []PluralValue:
+			for _, plural := range value {
+				if err := loc.setString(c, k, plural.Value, vars, plural.Form); err != nil {
+					errs = append(errs, fmt.Errorf("%s:%s parse plural %s: %w", loc.ID, k, plural.Form, err))
+				}
 			}
 
 		default:
-			return fmt.Errorf("%s:%s unexpected type of %T as value", loc.ID, key, value)
+			errs = append(errs, fmt.Errorf("%s:%s unexpected type of %T as value", loc.ID, k, value))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// sortedKeys returns the keys of the "keyValues" in the order which they should be stored,
+// the last one of a dotted key wins, e.g. the "nav.home" one over the "nav" map of a "home" key,
+// see `Options.KeyResolution`.
+func (loc *Locale) sortedKeys(keyValues Map) []string {
+	keys := make([]string, 0, len(keyValues))
+	for k := range keyValues {
+		keys = append(keys, k)
+	}
+
+	nested := loc.Options.KeyResolution == KeyResolutionNested
+	sort.Slice(keys, func(i, j int) bool {
+		if di, dj := strings.Count(keys[i], "."), strings.Count(keys[j], "."); di != dj {
+			if nested {
+				return di > dj
+			}
+
+			return di < dj
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
 }
 
 func (loc *Locale) setString(c *Catalog, key string, value string, vars []Var, form PluralForm) (err error) {
 	isPlural := form != nil
+	raw := value
+	marker, value := cutMarker(value)
 
-	// fmt.Printf("setStringVars: %s=%s\n", key, value)
-	msgs, vars := makeSelectfVars(value, vars, isPlural)
-	msgs = append(msgs, catalog.String(value))
+	var msgs []catalog.Message
+	if marker != TextMarker {
+		// fmt.Printf("setStringVars: %s=%s\n", key, value)
+		msgs, vars = makeSelectfVars(value, vars, isPlural)
+	} else {
+		vars = nil
+	}
+
+	escaped, hasVerbs := escapePercents(value)
+	isNamed := marker == "" && hasNamedPlaceholders(value)
+	if isNamed {
+		msgs = append(msgs, catalog.String(maskNamed(escaped)))
+	} else {
+		msgs = append(msgs, catalog.String(escaped))
+	}
+	// the static texts skip the printer, e.g. "Home" and "50% off".
+	isPlain := marker != TemplateMarker && len(vars) == 0 && !hasVerbs && !strings.Contains(value, "$")
 
 	m := &Message{
 		Locale: loc,
@@ -88,13 +229,33 @@ func (loc *Locale) setString(c *Catalog, key string, value string, vars []Var, f
 		Value:  value,
 		Vars:   vars,
 		Plural: isPlural,
+		marker: marker,
 	}
 
 	var (
 		renderer, pluralRenderer Renderer = m, m
 	)
 
-	if stringIsTemplateValue(value, loc.Options.Left, loc.Options.Right) {
+	// the marker takes precedence over the delimiters.
+	isTemplate := marker == TemplateMarker ||
+		(marker == "" && stringIsTemplateValue(value, loc.Options.Left, loc.Options.Right))
+	if !isTemplate && marker == "" && loc.Options.StrictTemplates &&
+		(strings.Contains(value, loc.Options.Left) || strings.Contains(value, loc.Options.Right)) {
+		// e.g. a missing closing delimiter.
+		_, err = template.New(key).Delims(loc.Options.Left, loc.Options.Right).Funcs(loc.FuncMap).Parse(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if marker == TextMarker {
+		text := &Message{Locale: loc, Key: key, Value: value, marker: marker, plain: true, text: value}
+
+		pluralRenderer = text
+		if !isPlural {
+			renderer = text
+		}
+	} else if isTemplate {
 		t, err := NewTemplate(c, m)
 		if err != nil {
 			return err
@@ -106,9 +267,16 @@ func (loc *Locale) setString(c *Catalog, key string, value string, vars []Var, f
 		}
 	} else {
 		if isPlural {
-			pluralRenderer, err = newIndependentPluralRenderer(c, loc, key, msgs...)
-			if err != nil {
-				return fmt.Errorf("<%s = %s>: %w", key, value, err)
+			if isPlain {
+				pluralRenderer = &Message{Locale: loc, Key: key, Value: raw, plain: true, text: unescapePercents(value), named: isNamed}
+			} else {
+				r, err := newIndependentPluralRenderer(c, loc, key, raw, msgs...)
+				if err != nil {
+					return fmt.Errorf("<%s = %s>: %w", key, value, err)
+				}
+
+				r.named = isNamed
+				pluralRenderer = r
 			}
 		} else {
 			// let's make normal keys direct fire:
@@ -116,6 +284,13 @@ func (loc *Locale) setString(c *Catalog, key string, value string, vars []Var, f
 			if err = c.Set(loc.tag, key, msgs...); err != nil {
 				return fmt.Errorf("<%s = %s>: %w", key, value, err)
 			}
+
+			if isPlain {
+				m.plain = true
+				m.text = unescapePercents(value)
+			}
+
+			m.named = isNamed
 		}
 
 	}
@@ -154,25 +329,247 @@ func (loc *Locale) Language() string {
 	return loc.ID
 }
 
+// Exists reports whether a translation for the "key" exists on this Locale.
+// Nested keys are separated by dot, e.g. "nav.more.what",
+// a literal dot of a key may be escaped, e.g. `files.config\.yaml`, see `UnescapeKey`.
+func (loc *Locale) Exists(key string) bool {
+	_, ok := loc.Messages[UnescapeKey(key)]
+	return ok
+}
+
+// UnescapeKey returns the "key" with its escaped dots unescaped, e.g. `files.config\.yaml`
+// to "files.config.yaml", the key of the "config.yaml" key of the "files" map.
+// The keys are stored by their full dotted path, so a key segment with a literal dot,
+// e.g. a file name, is found by its escaped and its plain form,
+// the locale files may escape it too.
+func UnescapeKey(key string) string {
+	if !strings.Contains(key, `\.`) {
+		return key
+	}
+
+	return strings.ReplaceAll(key, `\.`, ".")
+}
+
+// Keys returns the sorted translation keys of this Locale.
+// Nested keys are separated by dot, e.g. "nav.more.what".
+func (loc *Locale) Keys() []string {
+	keys := make([]string, 0, len(loc.Messages))
+	for key := range loc.Messages {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// String returns the language and the number of the translation messages,
+// e.g. "en-US (42 messages)", for logging.
+func (loc *Locale) String() string {
+	return fmt.Sprintf("%s (%d messages)", loc.ID, len(loc.Messages))
+}
+
+// MarshalJSON completes the json.Marshaler interface.
+// It returns the language and its raw translation messages,
+// e.g. {"lang":"en-US","messages":{"nav":{"home":"Home"}}}.
+// The nested keys are preserved and the plural messages are objects of their forms,
+// e.g. {"one":"%d item","other":"%d items"}.
+func (loc *Locale) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Lang     string                 `json:"lang"`
+		Messages map[string]interface{} `json:"messages"`
+	}{
+		Lang:     loc.ID,
+		Messages: loc.messagesTree(),
+	})
+}
+
+// RawMessages returns the raw translation messages by their dotted keys,
+// the plural messages are maps of their forms, e.g. {"one":"%d item","other":"%d items"}.
+func (loc *Locale) RawMessages() map[string]interface{} {
+	messages := make(map[string]interface{}, len(loc.Messages))
+	for key, r := range loc.Messages {
+		if value := rawMessageValue(r); value != nil {
+			// the gender messages are the maps of their forms, e.g. "key.female".
+			messages[key] = value
+		}
+	}
+
+	return messages
+}
+
+// messagesTree returns the raw translation messages nested by their dotted keys.
+func (loc *Locale) messagesTree() map[string]interface{} {
+	return NestMessages(loc.RawMessages())
+}
+
+// NestMessages returns the "messages" nested by their dotted keys,
+// e.g. {"nav.home":"Home"} to {"nav":{"home":"Home"}}.
+func NestMessages(messages map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(messages))
+	for key := range messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tree := make(map[string]interface{})
+
+	// sorted, so the parent keys are visited before their children.
+keys:
+	for _, key := range keys {
+		value := messages[key]
+
+		node, parts := tree, strings.Split(key, ".")
+		for i, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				if _, exists := node[part]; exists {
+					// a message exists on that key already, keep the rest of the key as it is.
+					node[strings.Join(parts[i:], ".")] = value
+					continue keys
+				}
+
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+
+			node = child
+		}
+
+		node[parts[len(parts)-1]] = value
+	}
+
+	return tree
+}
+
+func rawMessageValue(r Renderer) interface{} {
+	switch v := r.(type) {
+	case *Message:
+		if !v.Plural || len(v.Plurals) == 0 {
+			return joinMarker(v.marker, v.Value)
+		}
+
+		plurals := make(map[string]interface{}, len(v.Plurals))
+		for _, plural := range v.Plurals {
+			plurals[plural.Form.String()] = rawMessageValue(plural.Renderer)
+		}
+
+		return plurals
+	case *Template:
+		return joinMarker(v.marker, v.Value)
+	case *independentPluralRenderer:
+		return v.value
+	default:
+		return nil
+	}
+}
+
+// CanFallback reports whether the "key" can fallback to the default language,
+// see `Options.NoFallbackPrefixes`.
+func (loc *Locale) CanFallback(key string) bool {
+	for _, prefix := range loc.Options.NoFallbackPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // GetMessage should return translated text based on the given "key".
 func (loc *Locale) GetMessage(key string, args ...interface{}) string {
 	return loc.getMessage(loc.ID, key, args...)
 }
 
 func (loc *Locale) getMessage(langInput, key string, args ...interface{}) string {
+	result, err := loc.getMessageError(langInput, key, args...)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		result = err.Error()
+	}
+
+	return result
+}
+
+// ContextSeparator separates the key of a context-qualified message from its context,
+// e.g. the "Post@verb" and "Post@noun" keys, see `Locale.GetMessageContext`.
+const ContextSeparator = "@"
+
+// GetMessageContext same as `GetMessage` but it returns the message of the "key" in the "ctx" context,
+// e.g. the verb or the noun of the same English word, through the "key@ctx" key
+// of the locale files, or the msgctxt of the gettext ones.
+// The message of the "key" itself is returned if the "key@ctx" one was not found.
+func (loc *Locale) GetMessageContext(ctx, key string, args ...interface{}) string {
+	if ctx != "" {
+		if ctxKey := key + ContextSeparator + ctx; loc.Exists(ctxKey) {
+			return loc.GetMessage(ctxKey, args...)
+		}
+	}
+
+	return loc.GetMessage(key, args...)
+}
+
+// GetMessageError same as `GetMessage` but it returns an error
+// wrapping the ErrKeyNotFound if the "key" was not found or the render error.
+func (loc *Locale) GetMessageError(key string, args ...interface{}) (string, error) {
+	return loc.getMessageError(loc.ID, key, args...)
+}
+
+// GetPluralMessage same as `GetMessage` but the plural form of the message
+// is selected by the "count" instead of the first of the "args".
+// The "count" is the first argument of the printf-style messages
+// and the first of the "args" is the data of the template ones.
+func (loc *Locale) GetPluralMessage(key string, count int, args ...interface{}) string {
+	result, err := loc.GetPluralMessageError(key, count, args...)
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		result = err.Error()
+	}
+
+	return result
+}
+
+// GetPluralMessageError same as `GetPluralMessage` but it returns an error
+// wrapping the ErrKeyNotFound if the "key" was not found or the render error.
+func (loc *Locale) GetPluralMessageError(key string, count int, args ...interface{}) (string, error) {
+	return loc.ResolvePluralMessageError(nil, key, count, args...)
+}
+
+// ResolveMessageError same as `GetMessageError` but the {{tr}} references of the message
+// which are not found on this Locale are resolved through the "r", e.g. by the fallback languages of an I18n.
+// A nil "r" resolves them like `GetMessageError` does.
+func (loc *Locale) ResolveMessageError(r *Resolver, key string, args ...interface{}) (string, error) {
+	return loc.getMessageErrorRef(reference{resolver: r}, loc.ID, key, args...)
+}
+
+// ResolvePluralMessageError same as `GetPluralMessageError` but the {{tr}} references of the message
+// which are not found on this Locale are resolved through the "r", see `ResolveMessageError`.
+func (loc *Locale) ResolvePluralMessageError(r *Resolver, key string, count int, args ...interface{}) (string, error) {
+	key = UnescapeKey(key)
 	if msg, ok := loc.Messages[key]; ok {
-		result, err := msg.Render(args...)
-		if err != nil {
-			result = err.Error()
+		return renderPlural(msg, reference{resolver: r}, count, args)
+	}
+
+	return loc.getMessageError(loc.ID, key, append([]interface{}{count}, args...)...)
+}
+
+func (loc *Locale) getMessageError(langInput, key string, args ...interface{}) (string, error) {
+	return loc.getMessageErrorRef(reference{}, langInput, key, args...)
+}
+
+func (loc *Locale) getMessageErrorRef(ref reference, langInput, key string, args ...interface{}) (string, error) {
+	key = UnescapeKey(key)
+	if msg, ok := loc.Messages[key]; ok {
+		if count, rest, ok := cutPluralCount(args); ok {
+			return renderPlural(msg, ref, count, rest)
 		}
 
-		return result
+		return renderRef(msg, ref, args)
 	}
 
+	err := fmt.Errorf("%w: %s: %s", ErrKeyNotFound, loc.ID, key)
+
 	if fn := loc.Options.DefaultMessageFunc; fn != nil {
 		// let langInput to be empty if that's the case.
-		return fn(langInput, loc.ID, key, args...)
+		return fn(langInput, loc.ID, key, args...), err
 	}
 
-	return ""
+	return "", err
 }
@@ -2,7 +2,11 @@ package internal
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
+
+	"golang.org/x/text/message"
 )
 
 // Renderer is responsible to render a translation based
@@ -24,6 +28,259 @@ type Message struct {
 	Plurals []*PluralMessage // plural forms by order.
 
 	Vars []Var
+
+	// marker is the interpolation marker of the Value, if any, see `TemplateMarker`.
+	marker string
+	// plain reports whether the Value is a static text,
+	// without template delimiters, variables or format verbs,
+	// so the text is rendered as it is, see `Locale.setString`.
+	plain bool
+	text  string
+	// named reports whether the Value contains named placeholders, e.g. "{name}",
+	// see `replaceNamed`.
+	named bool
+}
+
+// The interpolation markers of a message value. A value which starts with a marker,
+// followed by a space or a new line, is interpolated by its mode instead of the detected one,
+// e.g. "#!text 100% {{literal}}". The marker takes precedence over the template delimiters.
+const (
+	// TemplateMarker marks a template message, even without the template delimiters.
+	TemplateMarker = "#!template"
+	// PrintfMarker marks a printf-style message, its template delimiters
+	// and named placeholders are literal text.
+	PrintfMarker = "#!printf"
+	// TextMarker marks a static text message, its template delimiters,
+	// named placeholders, format verbs and variables are literal text.
+	TextMarker = "#!text"
+)
+
+// cutMarker returns the interpolation marker of the "value" and the rest of it.
+// An unknown marker is part of the value.
+func cutMarker(value string) (marker, rest string) {
+	if !strings.HasPrefix(value, "#!") {
+		return "", value
+	}
+
+	for _, marker := range []string{TemplateMarker, PrintfMarker, TextMarker} {
+		if rest, ok := strings.CutPrefix(value, marker); ok {
+			if rest == "" {
+				return marker, rest
+			}
+
+			if rest[0] == ' ' || rest[0] == '\n' {
+				return marker, rest[1:]
+			}
+		}
+	}
+
+	return "", value
+}
+
+// joinMarker is the opposite of `cutMarker`.
+func joinMarker(marker, value string) string {
+	if marker == "" {
+		return value
+	}
+
+	return marker + " " + value
+}
+
+var namedPlaceholderRegex = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// hasNamedPlaceholders reports whether the "value" contains a named placeholder, e.g. "{name}".
+func hasNamedPlaceholders(value string) bool {
+	return strings.IndexByte(value, '{') != -1 && namedPlaceholderRegex.MatchString(value)
+}
+
+// namedArgs returns the first map of the "args", which holds the values of the named placeholders,
+// and the rest of the "args", which are the arguments of the format verbs.
+func namedArgs(args []interface{}) (data map[string]interface{}, rest []interface{}, ok bool) {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case map[string]interface{}:
+			data = v
+		case map[string]string:
+			data = make(map[string]interface{}, len(v))
+			for key, value := range v {
+				data[key] = value
+			}
+		default:
+			continue
+		}
+
+		rest = make([]interface{}, 0, len(args)-1)
+		rest = append(rest, args[:i]...)
+		rest = append(rest, args[i+1:]...)
+		return data, rest, true
+	}
+
+	return nil, nil, false
+}
+
+// replaceNamed replaces the named placeholders of the "text", e.g. "{name}",
+// with their "data" values, formatted by the "printer", e.g. 1,000 for 1000 on "en-US".
+// The placeholders without a value are kept as they are.
+func replaceNamed(printer *message.Printer, text string, data map[string]interface{}) string {
+	return namedPlaceholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		value, ok := data[placeholder[1:len(placeholder)-1]]
+		if !ok {
+			return placeholder
+		}
+
+		return printer.Sprint(value)
+	})
+}
+
+// namedMark replaces the braces of the named placeholders on the catalog format
+// of the printf-style messages, e.g. "{name}" is stored as "\x00name\x00",
+// so a "{name}" of their formatted arguments is kept as it is, see `maskNamed`.
+const namedMark = "\x00"
+
+var maskedPlaceholderRegex = regexp.MustCompile(namedMark + `([A-Za-z_][A-Za-z0-9_]*)` + namedMark)
+
+// maskNamed returns the "format" with its named placeholders marked by the namedMark,
+// the ${variables} are kept as they are.
+func maskNamed(format string) string {
+	var (
+		b    strings.Builder
+		last int
+	)
+
+	for _, loc := range namedPlaceholderRegex.FindAllStringSubmatchIndex(format, -1) {
+		if loc[0] > 0 && format[loc[0]-1] == '$' {
+			continue // a ${variable}.
+		}
+
+		b.WriteString(format[last:loc[0]])
+		b.WriteString(namedMark)
+		b.WriteString(format[loc[2]:loc[3]])
+		b.WriteString(namedMark)
+		last = loc[1]
+	}
+
+	if last == 0 {
+		return format
+	}
+
+	b.WriteString(format[last:])
+	return b.String()
+}
+
+// unmaskNamed replaces the marked named placeholders of the formatted "text", see `maskNamed`,
+// with their "data" values, like the `replaceNamed` does, the ones without a value are restored,
+// e.g. to "{name}".
+func unmaskNamed(printer *message.Printer, text string, data map[string]interface{}) string {
+	if !strings.Contains(text, namedMark) {
+		return text
+	}
+
+	return maskedPlaceholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholder[len(namedMark) : len(placeholder)-len(namedMark)]
+		value, ok := data[name]
+		if !ok {
+			return "{" + name + "}"
+		}
+
+		return printer.Sprint(value)
+	})
+}
+
+// escapePercents returns the "value" with its literal percent signs escaped,
+// e.g. "50% off" to "50%% off", so they are not confused with the format verbs,
+// and reports whether it contains any format verb, e.g. "%d" or "%[1]s".
+// A percent sign followed by a space is a literal one too.
+func escapePercents(value string) (escaped string, hasVerbs bool) {
+	if strings.IndexByte(value, '%') == -1 {
+		return value, false
+	}
+
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		if i+1 < len(value) && value[i+1] == '%' {
+			b.WriteString("%%")
+			i++
+			continue
+		}
+
+		if n := formatVerbLen(value[i+1:]); n > 0 {
+			hasVerbs = true
+			b.WriteString(value[i : i+1+n])
+			i += n
+			continue
+		}
+
+		b.WriteString("%%")
+	}
+
+	return b.String(), hasVerbs
+}
+
+// unescapePercents returns the "value" with its escaped percent signs unescaped,
+// e.g. "100%%" to "100%", it is used for the messages without format verbs.
+func unescapePercents(value string) string {
+	return strings.ReplaceAll(value, "%%", "%")
+}
+
+// formatVerbLen returns the length of the format verb at the start of "s",
+// after its percent sign, including its flags, argument index, width and precision.
+// It returns zero if "s" does not start with a format verb.
+// A bare verb letter followed directly by another letter, e.g. the "o" of "50%off",
+// is literal text, an explicit argument index keeps it a verb, e.g. "%[1]dst".
+func formatVerbLen(s string) int {
+	i := 0
+	for i < len(s) && strings.IndexByte("+-#0", s[i]) != -1 {
+		i++
+	}
+
+	argIndex := func() {
+		if i < len(s) && s[i] == '[' {
+			if end := strings.IndexByte(s[i:], ']'); end != -1 {
+				i += end + 1
+			}
+		}
+	}
+
+	number := func() {
+		if i < len(s) && s[i] == '*' {
+			i++
+			return
+		}
+
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+	}
+
+	argIndex()
+	number()
+	if i < len(s) && s[i] == '.' {
+		i++
+		argIndex()
+		number()
+	}
+	argIndex()
+
+	if i < len(s) && strings.IndexByte("vTtbcdoOqxXUeEfFgGsp", s[i]) != -1 {
+		if i == 0 && len(s) > 1 && isASCIILetter(s[1]) {
+			return 0
+		}
+
+		return i + 1
+	}
+
+	return 0
+}
+
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
 }
 
 // AddPlural adds a plural message to the Plurals list.
@@ -57,16 +314,41 @@ func (m *Message) AddPlural(form PluralForm, r Renderer) {
 // It accepts arguments, which can resolve the pluralization type of the message
 // and its variables. If the Message is wrapped by a Template then the
 // first argument should be a map. The map key resolves to the pluralization
-// of the message is the "PluralCount". And for variables the user
+// of the message is the "PluralCount", see `Options.PluralCountKey`,
+// it always drives the plural selection. A leading integer argument selects
+// the plural form only when `Options.PositionalPluralCount` is true. And for variables the user
 // should set a message key which looks like: %VAR_NAME%Count, e.g. "DogsCount"
 // to set plural count for the "Dogs" variable, case-sensitive.
+// A static text message, without format verbs or variables, is returned as it is,
+// with its escaped "%%" percent signs unescaped, and its arguments are ignored.
 func (m *Message) Render(args ...interface{}) (string, error) {
+	return m.renderRef(reference{}, args...)
+}
+
+// renderRef renders the message as the "ref" reference, see `Locale.trFunc`.
+func (m *Message) renderRef(ref reference, args ...interface{}) (string, error) {
+	if m.named {
+		if data, rest, ok := namedArgs(args); ok {
+			if m.plain {
+				return replaceNamed(m.Locale.Printer, m.text, data), nil
+			}
+
+			// the placeholders are marked before the formatting,
+			// so the ones of the arguments, e.g. a "{x}" of a %s, are not replaced.
+			return unmaskNamed(m.Locale.Printer, m.Locale.Printer.Sprintf(m.Key, rest...), data), nil
+		}
+	}
+
+	if m.plain {
+		return m.text, nil
+	}
+
 	if m.Plural {
 		if len(args) > 0 {
-			if pluralCount, ok := findPluralCount(args[0]); ok {
+			if pluralCount, ok := findPluralCount(args[0], m.Locale.Options); ok {
 				for _, plural := range m.Plurals {
 					if plural.Form.MatchPlural(pluralCount) {
-						return plural.Renderer.Render(args...)
+						return renderRef(plural.Renderer, ref, args)
 					}
 				}
 
@@ -77,5 +359,44 @@ func (m *Message) Render(args ...interface{}) (string, error) {
 		return "", fmt.Errorf("key: %q: missing plural count argument", m.Key)
 	}
 
-	return m.Locale.Printer.Sprintf(m.Key, args...), nil
+	text := m.Locale.Printer.Sprintf(m.Key, args...)
+	if m.named {
+		text = unmaskNamed(m.Locale.Printer, text, nil)
+	}
+
+	return text, nil
+}
+
+// RenderPlural renders the plural form which matches the "count".
+// The "count" is the first argument of the printf-style messages
+// and the first of the "args" is the data of the template ones.
+// If the message is not a plural one then it's rendered by the same rules.
+func (m *Message) RenderPlural(count int, args ...interface{}) (string, error) {
+	return m.renderPluralRef(reference{}, count, args)
+}
+
+func (m *Message) renderPluralRef(ref reference, count int, args []interface{}) (string, error) {
+	if !m.Plural {
+		return renderWithCount(m, ref, count, args)
+	}
+
+	for _, plural := range m.Plurals {
+		if plural.Form.MatchPlural(count) {
+			return renderWithCount(plural.Renderer, ref, count, args)
+		}
+	}
+
+	return "", fmt.Errorf("key: %q: no registered plurals for <%d>", m.Key, count)
+}
+
+func renderWithCount(r Renderer, ref reference, count int, args []interface{}) (string, error) {
+	if _, ok := r.(*Template); ok {
+		if len(args) == 0 {
+			return renderRef(r, ref, []interface{}{count})
+		}
+
+		return renderRef(r, ref, args)
+	}
+
+	return renderRef(r, ref, append([]interface{}{count}, args...))
 }
@@ -0,0 +1,130 @@
+package internal
+
+// MetaKey is the key of the `MetaMap` of the loaded key values,
+// the file loaders store the metadata of the messages under that key, e.g. the gettext comments.
+// It is not a valid key of a locale file, so it is never confused with a message.
+const MetaKey = "\x00meta"
+
+// MessageMeta is the metadata of a message, e.g. for a translation editor,
+// when the format of its locale file supports it, see `Locale.Meta`.
+type MessageMeta struct {
+	// Description is the description of the message for the translators,
+	// e.g. the extracted (#.) comments of gettext or the "description" of go-i18n.
+	Description string `json:"description,omitempty"`
+	// Context is the disambiguating context of the message, e.g. the msgctxt of gettext.
+	Context string `json:"context,omitempty"`
+	// Comment is the comment of the message, e.g. the translator (#) comments
+	// of gettext or the comments of a YAML key.
+	Comment string `json:"comment,omitempty"`
+}
+
+// IsZero reports whether the "m" holds no metadata.
+func (m MessageMeta) IsZero() bool {
+	return m == MessageMeta{}
+}
+
+// merge returns the "m" with the non-empty fields of "other".
+func (m MessageMeta) merge(other MessageMeta) MessageMeta {
+	if other.Description != "" {
+		m.Description = other.Description
+	}
+
+	if other.Context != "" {
+		m.Context = other.Context
+	}
+
+	if other.Comment != "" {
+		m.Comment = other.Comment
+	}
+
+	return m
+}
+
+// MetaMap is the metadata of the messages by their (dotted) keys.
+type MetaMap map[string]MessageMeta
+
+// Merge merges the "other" metadata into "m", the non-empty fields of "other" win.
+func (m MetaMap) Merge(other MetaMap) {
+	for key, meta := range other {
+		m[key] = m[key].merge(meta)
+	}
+}
+
+// Meta returns the metadata of the "key", e.g. its translator comments,
+// if the format of its locale file supports it: the gettext comments,
+// the go-i18n descriptions and the YAML comments.
+// It returns an empty `MessageMeta` otherwise.
+func (loc *Locale) Meta(key string) MessageMeta {
+	return loc.meta[key]
+}
+
+// setMeta merges the "meta" into the Locale's metadata.
+func (loc *Locale) setMeta(meta MetaMap) {
+	if len(meta) == 0 {
+		return
+	}
+
+	if loc.meta == nil {
+		loc.meta = make(MetaMap, len(meta))
+	}
+
+	loc.meta.Merge(meta)
+}
+
+// CopyMeta copies the metadata of the "src" Locale to the "dst" one,
+// their keys are prefixed by "keyPrefix", e.g. for the merged locales of a chain.
+func CopyMeta(dst, src *Locale, keyPrefix string) {
+	if len(src.meta) == 0 {
+		return
+	}
+
+	meta := make(MetaMap, len(src.meta))
+	for key, m := range src.meta {
+		meta[keyPrefix+key] = m
+	}
+
+	dst.setMeta(meta)
+}
+
+// goI18nMessageFields are the fields of a go-i18n message, besides its plural forms.
+var goI18nMessageFields = map[string]struct{}{
+	"id":          {},
+	"hash":        {},
+	"description": {},
+	"leftdelim":   {},
+	"rightdelim":  {},
+}
+
+// cutGoI18nMessage reports whether the "m" is a go-i18n message,
+// e.g. {"description": "The greeting", "other": "Hello"},
+// it returns its description and its value: the "other" string if it is the only form,
+// or the map of its plural forms otherwise.
+func cutGoI18nMessage(m Map) (string, interface{}, bool) {
+	description, ok := m["description"].(string)
+	if !ok {
+		return "", nil, false
+	}
+
+	forms := make(Map, len(m))
+	for k, v := range m {
+		if _, ok := goI18nMessageFields[k]; ok {
+			continue
+		}
+
+		if _, ok := pluralCategories[k]; !ok {
+			return "", nil, false
+		}
+
+		forms[k] = v
+	}
+
+	if len(forms) == 0 {
+		return "", nil, false
+	}
+
+	if other, ok := forms["other"].(string); ok && len(forms) == 1 {
+		return description, other, true
+	}
+
+	return description, forms, true
+}
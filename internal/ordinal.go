@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+const (
+	// OrdinalKey is the key of the message which formats the ordinal numbers
+	// of a language, e.g. "%d." or its ordinal forms, see `Locale.FormatOrdinal`.
+	OrdinalKey = "Ordinal"
+	// OrdinalFormPrefix is the prefix of the ordinal plural forms,
+	// e.g. "ordinal_one", "ordinal_two", "ordinal_few" and "ordinal_other",
+	// which are selected by the CLDR ordinal rules of the language,
+	// e.g. "ordinal_few" for 3 and 23 in "en".
+	OrdinalFormPrefix = "ordinal_"
+)
+
+// ordinalPluralForm is a CLDR ordinal category form,
+// it matches a count based on the ordinal rules of its language.
+type ordinalPluralForm struct {
+	name     string
+	category plural.Form
+	tag      language.Tag
+}
+
+// decodeOrdinalForm returns the ordinal form of the "key", e.g. "ordinal_one".
+func decodeOrdinalForm(loc *Locale, key string) (PluralForm, bool) {
+	name, ok := strings.CutPrefix(key, OrdinalFormPrefix)
+	if !ok {
+		return nil, false
+	}
+
+	category, ok := pluralCategories[name]
+	if !ok {
+		return nil, false
+	}
+
+	form := &ordinalPluralForm{name: key, category: category}
+	if loc != nil {
+		form.tag = loc.tag
+	}
+
+	return form, true
+}
+
+func (f *ordinalPluralForm) String() string {
+	return f.name
+}
+
+// Less orders the ordinal forms by their category, the "ordinal_other" goes last.
+func (f *ordinalPluralForm) Less(next PluralForm) bool {
+	return ordinalCategoryIndex(f.String()) < ordinalCategoryIndex(next.String())
+}
+
+func ordinalCategoryIndex(form string) int {
+	return pluralCategoryIndex(strings.TrimPrefix(form, OrdinalFormPrefix))
+}
+
+func (f *ordinalPluralForm) MatchPlural(pluralCount int) bool {
+	if f.category == plural.Other {
+		return true
+	}
+
+	if pluralCount < 0 {
+		pluralCount = -pluralCount
+	}
+
+	return plural.Ordinal.MatchPlural(f.tag, pluralCount, 0, 0, 0, 0) == f.category
+}
+
+// englishOrdinalSuffixes are the built-in ordinal suffixes of English by their CLDR ordinal category.
+var englishOrdinalSuffixes = map[plural.Form]string{
+	plural.One:   "st",
+	plural.Two:   "nd",
+	plural.Few:   "rd",
+	plural.Other: "th",
+}
+
+// FormatOrdinal returns the ordinal number of "n" on the Locale's language, e.g. "3rd".
+// It renders the `OrdinalKey` message, if any, with "n" as its plural count,
+// e.g. Ordinal: "%d." or its ordinal forms:
+//
+//	Ordinal:
+//	  ordinal_one: "%[1]dst"
+//	  ordinal_other: "%[1]dth"
+//
+// Otherwise the English ordinals are built-in and the rest of the languages
+// return the number as it is.
+func (loc *Locale) FormatOrdinal(n int) string {
+	if msg, ok := loc.Messages[OrdinalKey]; ok {
+		var (
+			result string
+			err    error
+		)
+		if m, ok := msg.(*Message); ok {
+			result, err = m.RenderPlural(n)
+		} else {
+			result, err = renderWithCount(msg, reference{}, n, nil)
+		}
+
+		if err == nil {
+			return result
+		}
+	}
+
+	number := strconv.Itoa(n)
+	if base, _ := loc.tag.Base(); base.String() != "en" {
+		return number
+	}
+
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+
+	return number + englishOrdinalSuffixes[plural.Ordinal.MatchPlural(loc.tag, abs, 0, 0, 0, 0)]
+}
+
+// ordinal is the "ordinal" template function, see `FormatOrdinal`.
+func (loc *Locale) ordinal(n interface{}) string {
+	count, ok := toPluralCount(n)
+	if !ok {
+		return fmt.Sprint(n)
+	}
+
+	return loc.FormatOrdinal(count)
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"golang.org/x/text/message/catalog"
 )
@@ -19,6 +20,45 @@ type PluralCounter interface {
 	VarCount(name string) int
 }
 
+// PluralCount is a message argument which selects the plural form of a message by its type
+// instead of its position, e.g. Tr("en-US", "FreeDay", PluralCount(5), data).
+// It is removed from the arguments, the selected form is rendered like the `Locale.GetPluralMessage` does,
+// so it is never confused with an integer of the template or format data.
+type PluralCount int
+
+// cutPluralCount returns the count of the first PluralCount of the "args" and the rest of them.
+func cutPluralCount(args []interface{}) (int, []interface{}, bool) {
+	for i, arg := range args {
+		if count, ok := arg.(PluralCount); ok {
+			return int(count), append(args[:i:i], args[i+1:]...), true
+		}
+	}
+
+	return 0, args, false
+}
+
+// renderPlural renders the plural form of the "msg" which matches the "count",
+// see `Locale.GetPluralMessage`.
+func renderPlural(msg Renderer, ref reference, count int, args []interface{}) (string, error) {
+	switch m := msg.(type) {
+	case *Message:
+		return m.renderPluralRef(ref, count, args)
+	case *genderMessage:
+		// the count selects the plural form of the gender form.
+		return m.renderRef(ref, append([]interface{}{PluralCount(count)}, args...)...)
+	}
+
+	return renderWithCount(msg, ref, count, args)
+}
+
+// PluralValue is a translation value of an already decoded plural form.
+// A slice of PluralValue can be used as a message value, e.g. by a loader of a format
+// which defines its own plural rules (gettext), it is registered like the plural keys of a Map value.
+type PluralValue struct {
+	Form  PluralForm
+	Value string
+}
+
 // PluralMessage holds the registered Form and the corresponding Renderer.
 // It is used on the `Message.AddPlural` method.
 type PluralMessage struct {
@@ -28,19 +68,29 @@ type PluralMessage struct {
 
 type independentPluralRenderer struct {
 	key     string
+	value   string
 	printer *message.Printer
+	named   bool // see `Message.named`.
 }
 
-func newIndependentPluralRenderer(c *Catalog, loc *Locale, key string, msgs ...catalog.Message) (Renderer, error) {
+func newIndependentPluralRenderer(c *Catalog, loc *Locale, key, value string, msgs ...catalog.Message) (*independentPluralRenderer, error) {
 	builder := catalog.NewBuilder(catalog.Fallback(c.Locales[0].tag))
 	if err := builder.Set(loc.tag, key, msgs...); err != nil {
 		return nil, err
 	}
 	printer := message.NewPrinter(loc.tag, message.Catalog(builder))
-	return &independentPluralRenderer{key, printer}, nil
+	return &independentPluralRenderer{key: key, value: value, printer: printer}, nil
 }
 
 func (m *independentPluralRenderer) Render(args ...interface{}) (string, error) {
+	if m.named {
+		if data, rest, ok := namedArgs(args); ok {
+			return unmaskNamed(m.printer, m.printer.Sprintf(m.key, rest...), data), nil
+		}
+
+		return unmaskNamed(m.printer, m.printer.Sprintf(m.key, args...), nil), nil
+	}
+
 	return m.printer.Sprintf(m.key, args...), nil
 }
 
@@ -54,31 +104,87 @@ func (m *independentPluralRenderer) Render(args ...interface{}) (string, error)
 type PluralFormDecoder func(loc *Locale, key string) (PluralForm, bool)
 
 // DefaultPluralFormDecoder is the default `PluralFormDecoder`.
-// Supprots "zero", "one", "two", "other", "=x", "<x", ">x".
-var DefaultPluralFormDecoder = func(_ *Locale, key string) (PluralForm, bool) {
+// Supports the CLDR plural categories "zero", "one", "two", "few", "many" and "other",
+// which are selected based on the plural rules of the Locale's language,
+// and the "=x", "<x", ">x" forms.
+// The CLDR ordinal categories are supported too, prefixed by the "ordinal_",
+// e.g. "ordinal_few", see `OrdinalFormPrefix`.
+// For backwards compatibility, the "zero", "one" and "two" forms
+// always match the 0, 1 and 2 counts respectively.
+var DefaultPluralFormDecoder = func(loc *Locale, key string) (PluralForm, bool) {
+	if form, ok := decodeOrdinalForm(loc, key); ok {
+		return form, true
+	}
+
 	if isDefaultPluralForm(key) {
+		if category, ok := pluralCategories[key]; ok && loc != nil {
+			return &categoryPluralForm{
+				pluralForm: pluralForm(key),
+				category:   category,
+				tag:        loc.tag,
+				pluralFunc: loc.Options.PluralFunc,
+			}, true
+		}
+
 		return pluralForm(key), true
 	}
 
 	return nil, false
 }
 
+// pluralCategories are the CLDR plural categories by their key name.
+var pluralCategories = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// pluralCategory returns the CLDR plural category of the "count",
+// e.g. "one" or "other", based on the plural rules of the Locale's language
+// or the `Options.PluralFunc` one.
+// It returns an empty string if the "count" is not an integer.
+func (loc *Locale) pluralCategory(count interface{}) string {
+	if loc.Options.PluralFunc != nil {
+		return loc.Options.PluralFunc(loc.tag, count)
+	}
+
+	n, ok := toPluralCount(count)
+	if !ok {
+		return ""
+	}
+
+	if n < 0 {
+		n = -n
+	}
+
+	form := plural.Cardinal.MatchPlural(loc.tag, n, 0, 0, 0, 0)
+	for name, category := range pluralCategories {
+		if category == form {
+			return name
+		}
+	}
+
+	return "other"
+}
+
 func isDefaultPluralForm(s string) bool {
-	switch s {
-	case "zero", "one", "two", "other":
+	if _, ok := pluralCategories[s]; ok {
 		return true
-	default:
-		if len(s) > 1 {
-			ch := s[0]
-			if ch == '=' || ch == '<' || ch == '>' {
-				if isDigit(s[1]) {
-					return true
-				}
+	}
+
+	if len(s) > 1 {
+		ch := s[0]
+		if ch == '=' || ch == '<' || ch == '>' {
+			if isDigit(s[1]) {
+				return true
 			}
 		}
-
-		return false
 	}
+
+	return false
 }
 
 // A PluralForm is responsible to decode
@@ -137,31 +243,25 @@ func (f pluralForm) Less(next PluralForm) bool {
 		return true
 	}
 
-	if form1 == "zero" {
-		return true
-	}
-
-	if form2 == "zero" {
-		return false
-	}
-
-	if form1 == "one" {
-		return true
-	}
-
-	if form2 == "one" {
-		return false
-	}
-
-	if form1 == "two" {
-		return true
-	}
+	return pluralCategoryIndex(form1) < pluralCategoryIndex(form2)
+}
 
-	if form2 == "two" {
-		return false
+// pluralCategoryIndex returns the order of the "zero", "one", "two", "few" and "many" categories.
+func pluralCategoryIndex(form string) int {
+	switch form {
+	case "zero":
+		return 0
+	case "one":
+		return 1
+	case "two":
+		return 2
+	case "few":
+		return 3
+	case "many":
+		return 4
+	default:
+		return 5
 	}
-
-	return false
 }
 
 func (f pluralForm) MatchPlural(pluralCount int) bool {
@@ -195,6 +295,32 @@ func (f pluralForm) MatchPlural(pluralCount int) bool {
 	}
 }
 
+// categoryPluralForm is a CLDR plural category form,
+// it matches a count based on the plural rules of its language,
+// or the category which the "pluralFunc" returns, if not nil.
+type categoryPluralForm struct {
+	pluralForm
+	category   plural.Form
+	tag        language.Tag
+	pluralFunc func(tag language.Tag, count interface{}) string
+}
+
+func (f *categoryPluralForm) MatchPlural(pluralCount int) bool {
+	if f.pluralFunc != nil {
+		return f.category == plural.Other || f.pluralFunc(f.tag, pluralCount) == f.String()
+	}
+
+	if f.pluralForm.MatchPlural(pluralCount) { // "other" and the exact "zero", "one", "two".
+		return true
+	}
+
+	if pluralCount < 0 {
+		pluralCount = -pluralCount
+	}
+
+	return plural.Cardinal.MatchPlural(f.tag, pluralCount, 0, 0, 0, 0) == f.category
+}
+
 func makeSelectfVars(text string, vars []Var, insidePlural bool) ([]catalog.Message, []Var) {
 	newVars := sortVars(text, vars)
 	newVars = removeVarsDuplicates(newVars)
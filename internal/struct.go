@@ -0,0 +1,61 @@
+package internal
+
+import "reflect"
+
+// structTag is the struct field tag which holds the translation key of a field, see `Locale.Struct`.
+const structTag = "i18n"
+
+// Struct returns the translated labels of the "v" struct fields, by their field name,
+// e.g. for the labels of a form. The translation key of a field is its `i18n` tag:
+//
+//	type SignupForm struct {
+//		Email    string `i18n:"form.email"`
+//		Password string `i18n:"form.password"`
+//	}
+//
+//	labels := loc.Struct(SignupForm{}) // {"Email": "Email address", "Password": "Password"}
+//
+// The fields without an `i18n` tag, or with a "-" one, are skipped
+// and the fields of the embedded structs are included.
+// The "v" may be a pointer to a struct, it returns nil if "v" is not a struct.
+// A missing key is handled like `GetMessage` does, e.g. through the `DefaultMessageFunc`.
+func (loc *Locale) Struct(v interface{}) map[string]string {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	labels := make(map[string]string)
+	loc.structLabels(typ, labels)
+	return labels
+}
+
+func (loc *Locale) structLabels(typ reflect.Type, labels map[string]string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		key, ok := field.Tag.Lookup(structTag)
+		if !ok && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				loc.structLabels(embedded, labels)
+			}
+
+			continue
+		}
+
+		if key == "" || key == "-" {
+			continue
+		}
+
+		labels[field.Name] = loc.GetMessage(key)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"text/template"
 
+	"golang.org/x/text/cases"
 	"golang.org/x/text/message/catalog"
 )
 
@@ -23,6 +24,10 @@ const (
 	// are stored with,
 	// e.g. welcome.human.other_vars
 	VarsKeySuffix = "_vars"
+	// MaxReferenceDepth is the maximum depth of the nested {{tr "key"}} references
+	// of the template messages, it protects against cyclic references,
+	// e.g. a: {{tr "b"}} and b: {{tr "a"}}.
+	MaxReferenceDepth = 10
 )
 
 // Template is a Renderer which renders template messages.
@@ -30,12 +35,21 @@ type Template struct {
 	*Message
 	tmpl    *template.Template
 	bufPool *sync.Pool
+	// refs are the clones of the tmpl per reference, its depth and resolver,
+	// their "tr" function resolves the references of the next depth.
+	refs sync.Map // reference: *template.Template.
 }
 
 // NewTemplate returns a new Template message based on the
 // catalog and the base translation Message. See `Locale.Load` method.
+// The message is parsed once, on load, the `Render` executes the parsed template.
 func NewTemplate(c *Catalog, m *Message) (*Template, error) {
-	tmpl, err := template.New(m.Key).
+	tmpl := template.New(m.Key)
+	if missingKey := m.Locale.Options.MissingKey; missingKey != "" {
+		tmpl.Option("missingkey=" + missingKey)
+	}
+
+	tmpl, err := tmpl.
 		Delims(m.Locale.Options.Left, m.Locale.Options.Right).
 		Funcs(m.Locale.FuncMap).
 		Parse(m.Value)
@@ -87,6 +101,19 @@ func registerTemplateVars(c *Catalog, m *Message) error {
 // It renders a template message.
 // Each key has its own Template, plurals too.
 func (t *Template) Render(args ...interface{}) (string, error) {
+	return t.renderRef(reference{}, args...)
+}
+
+// renderRef renders the template as the "ref" reference.
+func (t *Template) renderRef(ref reference, args ...interface{}) (string, error) {
+	tmpl := t.tmpl
+	if ref != (reference{}) {
+		var err error
+		if tmpl, err = t.refTemplate(ref); err != nil {
+			return "", err
+		}
+	}
+
 	var (
 		data   interface{}
 		result string
@@ -101,7 +128,7 @@ func (t *Template) Render(args ...interface{}) (string, error) {
 	buf := t.bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 
-	if err := t.tmpl.Execute(buf, data); err != nil {
+	if err := tmpl.Execute(buf, data); err != nil {
 		t.bufPool.Put(buf)
 		return "", err
 	}
@@ -125,6 +152,103 @@ func (t *Template) Render(args ...interface{}) (string, error) {
 	return result, nil
 }
 
+// refTemplate returns the template which resolves the {{tr}} references of the "ref".
+func (t *Template) refTemplate(ref reference) (*template.Template, error) {
+	if v, ok := t.refs.Load(ref); ok {
+		return v.(*template.Template), nil
+	}
+
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Funcs(template.FuncMap{"tr": t.Locale.trFunc(ref)})
+
+	v, _ := t.refs.LoadOrStore(ref, tmpl)
+	return v.(*template.Template), nil
+}
+
+// Resolver resolves the {{tr}} references of the template messages
+// which are not found on their Locale, see `Locale.ResolveMessageError`.
+// It is compared by its pointer, the same Resolver should be reused across the calls.
+// A Resolver without a Fallback does not resolve them, e.g. on strict lookups.
+type Resolver struct {
+	// Fallback returns the locales, by order, to look up the "key"
+	// which was not found on the "loc", nil for no fallback.
+	Fallback func(loc *Locale, key string) []*Locale
+}
+
+// reference is a {{tr}} reference of a message,
+// its nesting depth and the resolver of its missing keys.
+type reference struct {
+	depth    int
+	resolver *Resolver
+}
+
+// refRenderer is implemented by the renderers which may
+// contain {{tr}} references, see `Locale.trFunc`.
+type refRenderer interface {
+	renderRef(ref reference, args ...interface{}) (string, error)
+}
+
+// renderRef renders the "r" as the "ref" reference, if it may contain {{tr}} references.
+func renderRef(r Renderer, ref reference, args []interface{}) (string, error) {
+	if rr, ok := r.(refRenderer); ok {
+		return rr.renderRef(ref, args...)
+	}
+
+	return r.Render(args...)
+}
+
+// trFunc returns the "tr" template function of the references of the next depth of "ref".
+// The referenced key is resolved on this locale and, if not found, on the locales of the `Resolver`.
+// Without a Resolver it falls back to the default language one,
+// unless a DefaultMessageFunc is set or the key should not fallback.
+func (loc *Locale) trFunc(ref reference) func(key string, args ...interface{}) (string, error) {
+	return func(key string, args ...interface{}) (string, error) {
+		if ref.depth >= MaxReferenceDepth {
+			return "", fmt.Errorf("tr: %q: reference depth exceeds %d, cyclic reference", key, MaxReferenceDepth)
+		}
+
+		key = UnescapeKey(key)
+		msg, ok := loc.Messages[key]
+		if !ok {
+			for _, fallbackLoc := range loc.refFallbacks(ref.resolver, key) {
+				if msg, ok = fallbackLoc.Messages[key]; ok {
+					break
+				}
+			}
+		}
+
+		if !ok {
+			return loc.getMessage(loc.ID, key, args...), nil
+		}
+
+		return renderRef(msg, reference{depth: ref.depth + 1, resolver: ref.resolver}, args)
+	}
+}
+
+// refFallbacks returns the locales to look up a {{tr}} reference "key" which was not found on this locale.
+func (loc *Locale) refFallbacks(r *Resolver, key string) []*Locale {
+	if r != nil {
+		if r.Fallback == nil {
+			return nil
+		}
+
+		return r.Fallback(loc, key)
+	}
+
+	if loc.Options.DefaultMessageFunc != nil || !loc.CanFallback(key) || loc.catalog == nil {
+		return nil
+	}
+
+	if def := loc.catalog.getLocale(0); def != nil && def != loc {
+		return []*Locale{def}
+	}
+
+	return nil
+}
+
 func findVarsCount(data interface{}, vars []Var) (args []interface{}) {
 	if data == nil {
 		return nil
@@ -167,41 +291,95 @@ func findVarsCount(data interface{}, vars []Var) (args []interface{}) {
 	return
 }
 
-func findPluralCount(data interface{}) (int, bool) {
+// findPluralCount returns the plural count of the "data",
+// the count of the data maps is stored under the `Options.PluralCountKey`.
+func findPluralCount(data interface{}, opts Options) (int, bool) {
 	if data == nil {
 		return -1, false
 	}
 
+	key := opts.PluralCountKey
+	if key == "" {
+		key = PluralCountKey
+	}
+
 	switch dataValue := data.(type) {
+	case PluralCount:
+		return int(dataValue), true
 	case PluralCounter:
 		if count := dataValue.PluralCount(); count >= 0 {
 			return count, true
 		}
 	case Map:
-		if v, ok := dataValue[PluralCountKey]; ok {
-			if count, ok := v.(int); ok {
-				return count, true
-			}
+		if v, ok := dataValue[key]; ok {
+			return toPluralCount(v)
 		}
 	case map[string]string:
-		if v, ok := dataValue[PluralCountKey]; ok {
-			count, err := strconv.Atoi(v)
-			if err != nil {
-				return -1, false
-			}
-
+		if v, ok := dataValue[key]; ok {
+			return toPluralCount(v)
+		}
+	case map[string]int:
+		if count, ok := dataValue[key]; ok {
 			return count, true
 		}
+	default:
+		if opts.PositionalPluralCount {
+			// when this is not a template data, the caller's argument should be args[1:] now.
+			return toIntegerCount(dataValue)
+		}
+	}
 
-	case map[string]int:
-		if count, ok := dataValue[PluralCountKey]; ok {
+	return -1, false
+}
+
+// toPluralCount converts an integer value, of any integer type, to a plural count,
+// the floats without a fractional part, e.g. decoded JSON numbers, and the numeric strings are accepted too.
+func toPluralCount(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case float32:
+		if float32(int(n)) == n {
+			return int(n), true
+		}
+	case float64:
+		if float64(int(n)) == n {
+			return int(n), true
+		}
+	case string:
+		if count, err := strconv.Atoi(n); err == nil {
 			return count, true
 		}
+	default:
+		return toIntegerCount(v)
+	}
+
+	return -1, false
+}
+
+// toIntegerCount converts a value of any integer type to a plural count.
+func toIntegerCount(v interface{}) (int, bool) {
+	switch n := v.(type) {
 	case int:
-		return dataValue, true // when this is not a template data, the caller's argument should be args[1:] now.
+		return n, true
+	case PluralCount:
+		return int(n), true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
 	case int64:
-		count := int(dataValue)
-		return count, true
+		return int(n), true
+	case uint:
+		return int(n), true
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint64:
+		return int(n), true
 	}
 
 	return -1, false
@@ -228,7 +406,25 @@ func stringIsTemplateValue(value, left, right string) bool {
 func getFuncs(loc *Locale) template.FuncMap {
 	// set the template funcs for this locale.
 	funcs := template.FuncMap{
-		"tr": loc.GetMessage,
+		"tr": loc.trFunc(reference{}),
+		// the casers are not safe for concurrent use, so a new one per call.
+		"upper": func(s string) string {
+			return cases.Upper(loc.tag).String(s)
+		},
+		"lower": func(s string) string {
+			return cases.Lower(loc.tag).String(s)
+		},
+		"title": func(s string) string {
+			return cases.Title(loc.tag).String(s)
+		},
+		"trim":     strings.TrimSpace,
+		"number":   loc.formatNumber,
+		"currency": loc.formatCurrency,
+		"date":     loc.FormatDate,
+		// e.g. {{if eq (pluralCategory .Count) "one"}}.
+		"pluralCategory": loc.pluralCategory,
+		// e.g. {{ordinal .Rank}}, see `Locale.FormatOrdinal`.
+		"ordinal": loc.ordinal,
 	}
 
 	if getFuncs := loc.Options.Funcs; getFuncs != nil {
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// unmarshalJSONC decodes a JSONC or JSON5 locale file, i.e. a JSON file with
+// line (//) and block (/* */) comments and trailing commas, e.g. for the translator notes:
+//
+//	{
+//	  // the title of the home page.
+//	  "title": "Home",
+//	  "nav": {
+//	    "more": "More", /* a button */
+//	  },
+//	}
+//
+// The rest of the JSON5 extensions, e.g. the unquoted keys and the single-quoted strings, are not supported.
+func unmarshalJSONC(data []byte, v interface{}) error {
+	stripped, err := stripJSONC(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(stripped, v)
+}
+
+// stripJSONC returns the "data" without the comments and the trailing commas,
+// the line numbers are kept so the json errors report the same line.
+func stripJSONC(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	// the index of the last comma on "out" which may be a trailing one, -1 if none.
+	comma := -1
+
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+
+		switch {
+		case ch == '"':
+			end := i + 1
+			for ; end < len(data) && data[end] != '"'; end++ {
+				if data[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(data) {
+				return nil, fmt.Errorf("jsonc: unterminated string")
+			}
+
+			out = append(out, data[i:end+1]...)
+			comma = -1
+			i = end
+		case ch == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case ch == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end == -1 {
+				return nil, fmt.Errorf("jsonc: unterminated comment")
+			}
+
+			comment := data[i : i+2+end+2]
+			out = append(out, bytes.Repeat([]byte{'\n'}, bytes.Count(comment, []byte{'\n'}))...)
+			i += len(comment) - 1
+		case ch == ',':
+			comma = len(out)
+			out = append(out, ch)
+		case ch == '}' || ch == ']':
+			if comma != -1 {
+				out[comma] = ' ' // drop the trailing comma.
+			}
+
+			out = append(out, ch)
+			comma = -1
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			out = append(out, ch)
+		default:
+			out = append(out, ch)
+			comma = -1
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,198 @@
+package i18n
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kataras/i18n/internal"
+
+	"golang.org/x/text/language"
+)
+
+// Lazy is a loader which loads the translations of a language
+// the first time that language is requested, e.g. for applications
+// with many languages but where most users only use a few of them.
+// The "loadOne" should return the key-value pairs of the "tag" language.
+// The optional "languages" are registered too, when they are not passed to the `New` function.
+//
+// Only the default language is loaded by the `New` function, so its errors are reported there.
+// The errors of the rest are logged and their translations fallback to the default language.
+//
+// See `LazyGlob` too.
+func Lazy(loadOne func(tag language.Tag) (Map, error), languages ...string) Loader {
+	return func(m *Matcher) (Localizer, error) {
+		for _, languageName := range languages {
+			parseLanguageName(m, languageName) // matches and adds the language tag to m.Languages.
+		}
+
+		return newLazyLocalizer(m, loadOne)
+	}
+}
+
+// LazyGlob is like `Glob` but the locale files of a language are read and parsed
+// the first time that language is requested, on startup only their file names are read.
+//
+// See `Lazy` too.
+func LazyGlob(globPattern string, options ...LoaderConfig) Loader {
+	return func(m *Matcher) (Localizer, error) {
+		assetNames, err := filepath.Glob(globPattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(assetNames) // the last file wins, see `Glob`.
+
+		languageOptions := DefaultLoaderConfig
+		if len(options) > 0 {
+			languageOptions = options[0]
+		}
+
+		if assetNames, err = excludeFiles(assetNames, languageOptions.Exclude); err != nil {
+			return nil, err
+		}
+
+		languageFiles := m.parseLanguageFiles(assetNames, languageOptions)
+
+		// by tag, the `Lazy` loaders load a language by its tag.
+		filesByTag := make(map[language.Tag][]string, len(languageFiles))
+		for langIndex, langFiles := range languageFiles {
+			filesByTag[m.Languages[langIndex]] = langFiles
+		}
+
+		loadOne := func(tag language.Tag) (Map, error) {
+			return loadFiles(tag.String(), filesByTag[tag], os.ReadFile, languageOptions.OnDuplicateKey)
+		}
+
+		return newLazyLocalizer(m, loadOne, options...)
+	}
+}
+
+// lazyLocalizer is the Localizer which the `Lazy` and `LazyGlob` loaders return.
+type lazyLocalizer struct {
+	*internal.Catalog
+	loadOne func(tag language.Tag) (Map, error)
+	// messages returns the messages of the `I18n.SetMessages`, see `Matcher.messages`.
+	messages func(tag language.Tag) []Map
+	loaded   sync.Map // *Locale: *lazyLoad.
+}
+
+// lazyLoad is the loading state of a locale of the lazyLocalizer.
+type lazyLoad struct {
+	once sync.Once
+	done atomic.Bool
+}
+
+func newLazyLocalizer(m *Matcher, loadOne func(tag language.Tag) (Map, error), opts ...LoaderConfig) (*lazyLocalizer, error) {
+	options := DefaultLoaderConfig
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.DefaultMessageFunc == nil {
+		options.DefaultMessageFunc = m.defaultMessageFunc
+	}
+
+	if options.PluralFunc == nil {
+		options.PluralFunc = m.pluralFunc
+	}
+
+	cat, err := internal.NewCatalog(m.Languages, options)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &lazyLocalizer{
+		Catalog:  cat,
+		loadOne:  loadOne,
+		messages: m.messages,
+	}
+
+	// load the default language now, so the caller gets its errors.
+	defaultLoc := cat.GetLocale(m.defaultIndex)
+	state := new(lazyLoad)
+	state.once.Do(func() {
+		err = l.load(defaultLoc)
+		state.done.Store(true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	l.loaded.Store(defaultLoc, state)
+
+	return l, nil
+}
+
+// clone returns a copy of the localizer, see `Catalog.Clone`.
+// The loaded locales are not loaded again.
+func (l *lazyLocalizer) clone() *lazyLocalizer {
+	isLoaded := func(loc *Locale) bool {
+		v, ok := l.loaded.Load(loc)
+		return ok && v.(*lazyLoad).done.Load()
+	}
+
+	c := &lazyLocalizer{
+		Catalog:  l.Catalog.Clone(isLoaded),
+		loadOne:  l.loadOne,
+		messages: l.messages,
+	}
+
+	for _, loc := range l.Catalog.Locales {
+		if isLoaded(loc) {
+			state := new(lazyLoad)
+			state.once.Do(func() {})
+			state.done.Store(true)
+			c.loaded.Store(c.Catalog.Locales[loc.Index()], state)
+		}
+	}
+
+	return c
+}
+
+// GetLocale completes the `Localizer` interface.
+// It loads the translations of the locale, once.
+func (l *lazyLocalizer) GetLocale(index int) *Locale {
+	loc := l.Catalog.GetLocale(index)
+	if loc == nil {
+		return nil
+	}
+
+	v, ok := l.loaded.Load(loc)
+	if !ok {
+		v, _ = l.loaded.LoadOrStore(loc, new(lazyLoad))
+	}
+
+	state := v.(*lazyLoad)
+	state.once.Do(func() {
+		if err := l.load(loc); err != nil {
+			log.Printf("i18n: lazy load: %s: %v", loc.Language(), err)
+		}
+		state.done.Store(true)
+	})
+
+	return loc
+}
+
+// load stores the translations of the "loc" and then
+// the messages of the `I18n.SetMessages`, which override them.
+func (l *lazyLocalizer) load(loc *Locale) error {
+	keyValues, err := l.loadOne(*loc.Tag())
+	if err == nil && len(keyValues) > 0 {
+		if err = l.Catalog.Store(loc.Index(), keyValues); err != nil {
+			err = &LoadError{Lang: loc.Language(), Err: err}
+		}
+	}
+
+	if l.messages != nil {
+		for _, messages := range l.messages(*loc.Tag()) {
+			if storeErr := l.Catalog.Store(loc.Index(), messages); storeErr != nil {
+				return errors.Join(err, &LoadError{Lang: loc.Language(), Err: storeErr})
+			}
+		}
+	}
+
+	return err
+}
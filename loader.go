@@ -1,12 +1,20 @@
 package i18n
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
-	"io"
 	"io/fs"
+	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kataras/i18n/internal"
@@ -22,25 +30,77 @@ import (
 // See `Glob` and `Assets` package-level functions.
 type LoaderConfig = internal.Options
 
+// KeyResolution describes which message of a dotted key wins when a locale defines it
+// both as a literal flat key, e.g. "nav.home": "Home", and as a nested one, e.g. nav: {home: "Home"},
+// see `LoaderConfig.KeyResolution`.
+type KeyResolution = internal.KeyResolution
+
+const (
+	// KeyResolutionFlat stores the message of the literal flat key over the nested one, the default.
+	KeyResolutionFlat = internal.KeyResolutionFlat
+	// KeyResolutionNested stores the message of the nested key over the literal flat one.
+	KeyResolutionNested = internal.KeyResolutionNested
+)
+
 // Glob accepts a glob pattern (see: https://golang.org/pkg/path/filepath/#Glob)
 // and loads the locale files based on any "options".
 //
+// The files are loaded in the lexical order of their names, on every OS,
+// when more than one file of a language define the same key the last one wins,
+// e.g. the "en-US/b.yml" overrides the "en-US/a.yml", see `LoaderConfig.OnDuplicateKey`.
+//
 // The "globPattern" input parameter is a glob pattern which the default loader should
 // search and load for locale files.
 //
-// See `New` and `LoaderConfig` too.
+// See `Globs`, `New` and `LoaderConfig` too.
 func Glob(globPattern string, options ...LoaderConfig) Loader {
-	assetNames, err := filepath.Glob(globPattern)
-	if err != nil {
-		panic(err)
+	return Globs([]string{globPattern}, options...)
+}
+
+// Globs same as `Glob` but it accepts more than one glob pattern,
+// e.g. the shared locale files and the feature ones:
+//
+//	Globs([]string{"./common/*/*", "./features/*/*/*"})
+//
+// The files of all patterns are loaded together, once each, even if more than one pattern matches them,
+// in the lexical order of their names, see `Glob`.
+// The patterns are searched on every load, so the `I18n.Reload` method loads the new files too.
+func Globs(globPatterns []string, options ...LoaderConfig) Loader {
+	for _, globPattern := range globPatterns {
+		if _, err := filepath.Match(globPattern, ""); err != nil {
+			panic(err)
+		}
 	}
 
-	return load(assetNames, os.ReadFile, options...)
+	return func(m *Matcher) (Localizer, error) {
+		var assetNames []string
+		seen := make(map[string]struct{})
+
+		for _, globPattern := range globPatterns {
+			matches, err := filepath.Glob(globPattern)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, name := range matches {
+				key := filepath.Clean(name)
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				assetNames = append(assetNames, name)
+			}
+		}
+
+		return load(assetNames, os.ReadFile, options...)(m)
+	}
 }
 
 // FS is a virtual or local locale file system Loader.
-// It accepts embed.FS or fs.FS or http.FileSystem.
-// The "pattern" is a classic glob pattern.
+// It accepts any fs.FS implementation, e.g. embed.FS, os.DirFS or fstest.MapFS.
+// The "pattern" is a classic glob pattern, see `fs.Glob`.
+// The files are loaded in the lexical order of their names, see `Glob`.
 //
 // See `Glob`, `Assets`, `New` and `LoaderConfig` too.
 func FS(fileSystem fs.FS, pattern string, options ...LoaderConfig) (Loader, error) {
@@ -52,12 +112,7 @@ func FS(fileSystem fs.FS, pattern string, options ...LoaderConfig) (Loader, erro
 	}
 
 	assetFunc := func(name string) ([]byte, error) {
-		f, err := fileSystem.Open(name)
-		if err != nil {
-			return nil, err
-		}
-
-		return io.ReadAll(f)
+		return fs.ReadFile(fileSystem, name)
 	}
 
 	return load(assetNames, assetFunc, options...), nil
@@ -67,6 +122,7 @@ func FS(fileSystem fs.FS, pattern string, options ...LoaderConfig) (Loader, erro
 // another a function that should return the contents of a specific file
 // and any Loader options. Go-bindata usage.
 // It returns a valid `Loader` which loads and maps the locale files.
+// The files are loaded in the lexical order of their names, see `Glob`.
 //
 // See `Glob`, `Assets`, `New` and `LoaderConfig` too.
 func Assets(assetNames func() []string, asset func(string) ([]byte, error), options ...LoaderConfig) Loader {
@@ -98,6 +154,14 @@ func KV(langMap LangMap, opts ...LoaderConfig) Loader {
 			options = opts[0]
 		}
 
+		if options.DefaultMessageFunc == nil {
+			options.DefaultMessageFunc = m.defaultMessageFunc
+		}
+
+		if options.PluralFunc == nil {
+			options.PluralFunc = m.pluralFunc
+		}
+
 		languageIndexes := make([]int, 0, len(langMap))
 		keyValuesMulti := make([]Map, 0, len(langMap))
 
@@ -112,17 +176,17 @@ func KV(langMap LangMap, opts ...LoaderConfig) Loader {
 			return nil, err
 		}
 
-		for _, langIndex := range languageIndexes {
+		for i, langIndex := range languageIndexes {
 			if langIndex == -1 {
 				// If loader has more languages than defined for use in New function,
 				// e.g. when New(KV(m), "en-US") contains el-GR and en-US but only "en-US" passed.
 				continue
 			}
 
-			kv := keyValuesMulti[langIndex]
+			kv := keyValuesMulti[i]
 			err := cat.Store(langIndex, kv)
 			if err != nil {
-				return nil, err
+				return nil, &LoadError{Lang: m.Languages[langIndex].String(), Err: err}
 			}
 		}
 
@@ -136,6 +200,162 @@ func KV(langMap LangMap, opts ...LoaderConfig) Loader {
 	}
 }
 
+// FromMap is a loader which accepts the translations defined in Go code, without any files,
+// the "m" key is a language code and its value the dictionary of that language,
+// e.g. for compiled-in strings or the loader tests.
+// Its messages are compiled like the ones of the locale files, e.g. the templates.
+//
+// Unlike the `KV` loader, the nested dictionaries may be any Go map of string keys,
+// e.g. a map[string]string or a map[string]map[string]string,
+// and the numbers and booleans are stored as their text, e.g. "1" for 1.
+//
+// Example Code:
+//
+//	I18n, err := New(FromMap(map[string]map[string]interface{}{
+//		"en": {"hello": "Hello {{.Name}}", "nav": map[string]string{"home": "Home"}},
+//		"el": {"hello": "Γειά {{.Name}}", "nav": map[string]string{"home": "Αρχική"}},
+//	}), "en", "el")
+func FromMap(m map[string]map[string]interface{}, opts ...LoaderConfig) Loader {
+	langMap := make(LangMap, len(m))
+	for languageName, dictionary := range m {
+		langMap[languageName] = fromGoMap(reflect.ValueOf(dictionary))
+	}
+
+	return KV(langMap, opts...)
+}
+
+// fromGoMap converts a Go map of string keys, and its nested ones, to a Map, see `FromMap`.
+func fromGoMap(v reflect.Value) Map {
+	m := make(Map, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if key == internal.MetaKey {
+			m[key] = iter.Value().Interface()
+			continue
+		}
+
+		m[key] = fromGoValue(iter.Value())
+	}
+
+	return m
+}
+
+// fromGoValue converts a value of a Go map to a value of a Map, see `FromMap`.
+// The values which are not supported are kept as they are, so the loading reports them.
+func fromGoValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String {
+			return fromGoMap(v)
+		}
+	case reflect.String:
+		return v.String()
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface())
+	}
+
+	return v.Interface()
+}
+
+// DB is a loader which reads the translations from a database, or any other source,
+// through the "query" function. The "query" is called for each registered language
+// and should return its key-value pairs, flat (e.g. "nav.home") or nested maps,
+// template values are supported like on the file loaders.
+// The optional "languages" are registered too, when they are not passed to the `New` function.
+//
+// Call the `I18n.Reload` method to query the translations again,
+// e.g. after they are edited.
+//
+// Example Code:
+//
+//	loader := DB(func(lang string) (map[string]interface{}, error) {
+//		return queryTranslations(db, lang)
+//	}, "en-US", "el-GR")
+//	I18n, err := New(loader)
+func DB(query func(lang string) (map[string]interface{}, error), languages ...string) Loader {
+	return func(m *Matcher) (Localizer, error) {
+		for _, languageName := range languages {
+			parseLanguageName(m, languageName) // matches and adds the language tag to m.Languages.
+		}
+
+		options := DefaultLoaderConfig
+		options.DefaultMessageFunc = m.defaultMessageFunc
+		options.PluralFunc = m.pluralFunc
+
+		cat, err := internal.NewCatalog(m.Languages, options) // a locale per registered language.
+		if err != nil {
+			return nil, err
+		}
+
+		for langIndex, tag := range m.Languages {
+			lang := tag.String()
+
+			keyValues, err := query(lang)
+			if err != nil {
+				return nil, fmt.Errorf("query %s: %w", lang, err)
+			}
+
+			if len(keyValues) == 0 {
+				continue
+			}
+
+			if err = cat.Store(langIndex, keyValues); err != nil {
+				return nil, &LoadError{Lang: lang, Err: err}
+			}
+		}
+
+		return cat, nil
+	}
+}
+
+// excludeFiles returns the "fileNames" which do not match any of the "patterns", see `LoaderConfig.Exclude`.
+func excludeFiles(fileNames []string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return fileNames, nil
+	}
+
+	included := make([]string, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		name := filepath.ToSlash(fileName)
+		excluded := false
+
+		for _, pattern := range patterns {
+			subject := name
+			if !strings.Contains(pattern, "/") {
+				subject = path.Base(name)
+			}
+
+			matched, err := path.Match(strings.TrimPrefix(pattern, "./"), strings.TrimPrefix(subject, "./"))
+			if err != nil {
+				return nil, fmt.Errorf("exclude: %s: %w", pattern, err)
+			}
+
+			if matched {
+				excluded = true
+				break
+			}
+		}
+
+		if !excluded {
+			included = append(included, fileName)
+		}
+	}
+
+	return included, nil
+}
+
 // DefaultLoaderConfig represents the default loader configuration.
 var DefaultLoaderConfig = LoaderConfig{
 	Left:               "{{",
@@ -153,59 +373,59 @@ var DefaultLoaderConfig = LoaderConfig{
 //
 // See `FS`, Glob`, `Assets` and `LoaderConfig` too.
 func load(assetNames []string, asset func(string) ([]byte, error), opts ...LoaderConfig) Loader {
-	return func(m *Matcher) (Localizer, error) {
-		languageFiles, err := m.ParseLanguageFiles(assetNames)
-		if err != nil {
-			return nil, err
-		}
+	// sort a copy, so the last file wins on every OS and loader, see `Glob`.
+	assetNames = append([]string(nil), assetNames...)
+	sort.Strings(assetNames)
 
+	return func(m *Matcher) (Localizer, error) {
 		options := DefaultLoaderConfig
 
 		if len(opts) > 0 {
 			options = opts[0]
 		}
 
+		assetNames, err := excludeFiles(assetNames, options.Exclude)
+		if err != nil {
+			return nil, err
+		}
+
+		languageFiles := m.parseLanguageFiles(assetNames, options)
+		if err := parseXLIFFLanguages(m, assetNames, languageFiles, asset); err != nil {
+			return nil, err
+		}
+
 		if options.DefaultMessageFunc == nil {
 			options.DefaultMessageFunc = m.defaultMessageFunc
 		}
 
+		if options.PluralFunc == nil {
+			options.PluralFunc = m.pluralFunc
+		}
+
 		cat, err := internal.NewCatalog(m.Languages, options)
 		if err != nil {
 			return nil, err
 		}
 
+		var errs []error
 		for langIndex, langFiles := range languageFiles {
-			keyValues := make(map[string]interface{})
-
-			for _, fileName := range langFiles {
-				unmarshal := yaml.Unmarshal
-				if idx := strings.LastIndexByte(fileName, '.'); idx > 1 {
-					switch fileName[idx:] {
-					case ".toml", ".tml":
-						unmarshal = toml.Unmarshal
-					case ".json":
-						unmarshal = json.Unmarshal
-					case ".ini":
-						unmarshal = unmarshalINI
-					}
-				}
+			lang := m.Languages[langIndex].String()
 
-				b, err := asset(fileName)
-				if err != nil {
-					return nil, err
-				}
-
-				if err = unmarshal(b, &keyValues); err != nil {
-					return nil, err
-				}
+			keyValues, err := loadFiles(lang, langFiles, asset, options.OnDuplicateKey)
+			if err != nil {
+				errs = append(errs, err)
+				continue // report all broken files at once.
 			}
 
-			err = cat.Store(langIndex, keyValues)
-			if err != nil {
-				return nil, err
+			if err = cat.Store(langIndex, keyValues); err != nil {
+				errs = append(errs, &LoadError{Lang: lang, Err: err})
 			}
 		}
 
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+
 		if n := len(cat.Locales); n == 0 {
 			return nil, fmt.Errorf("locales not found in %s", strings.Join(assetNames, ", "))
 		} else if options.Strict && n < len(m.Languages) {
@@ -216,6 +436,291 @@ func load(assetNames []string, asset func(string) ([]byte, error), opts ...Loade
 	}
 }
 
+// parseXLIFFLanguages adds the XLIFF files whose names do not contain a language code
+// to the "languageFiles" based on their target language, the trgLang attribute.
+func parseXLIFFLanguages(m *Matcher, assetNames []string, languageFiles map[int][]string, asset func(string) ([]byte, error)) error {
+	parsed := make(map[string]struct{}, len(assetNames))
+	for _, langFiles := range languageFiles {
+		for _, fileName := range langFiles {
+			parsed[fileName] = struct{}{}
+		}
+	}
+
+	for _, fileName := range assetNames {
+		if _, ok := parsed[fileName]; ok || !isXLIFF(fileName) {
+			continue
+		}
+
+		b, err := asset(fileName)
+		if err == nil {
+			b, err = decodeFile(b)
+		}
+		if err != nil {
+			return &LoadError{File: fileName, Err: err}
+		}
+
+		if lang := xliffTargetLanguage(b); lang != "" {
+			if index := parseLanguageName(m, lang); index != -1 {
+				languageFiles[index] = append(languageFiles[index], fileName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadFiles decodes and merges the locale files of a language
+// based on their extensions, the yaml is the default format.
+// The nested values are merged, a key defined by more than one file
+// is handled based on the "onDuplicate", the last file wins by default.
+// It returns the `LoadError` of each file which failed, joined.
+func loadFiles(lang string, langFiles []string, asset func(string) ([]byte, error), onDuplicate DuplicateKey) (map[string]interface{}, error) {
+	keyValues := make(map[string]interface{})
+	keyFiles := make(map[string]string) // key: the file which defined it.
+	meta := make(internal.MetaMap)
+
+	var errs []error
+	for _, fileName := range langFiles {
+		unmarshal := unmarshalYAML
+		if idx := strings.LastIndexByte(fileName, '.'); idx > 1 {
+			switch fileName[idx:] {
+			case ".toml", ".tml":
+				unmarshal = toml.Unmarshal
+			case ".json":
+				unmarshal = json.Unmarshal
+			case ".jsonc", ".json5":
+				unmarshal = unmarshalJSONC
+			case ".ini":
+				unmarshal = unmarshalINI
+			case ".properties":
+				unmarshal = unmarshalProperties
+			case ".po":
+				unmarshal = unmarshalPO
+			case ".mo":
+				unmarshal = unmarshalMO
+			case ".xlf", ".xliff":
+				unmarshal = unmarshalXLIFF
+			case ".ftl":
+				unmarshal = unmarshalFluent
+			case ".xml":
+				unmarshal = unmarshalXML
+			case ".strings":
+				unmarshal = unmarshalAppleStrings
+			case ".stringsdict":
+				unmarshal = unmarshalStringsDict
+			}
+		}
+
+		b, err := asset(fileName)
+		if err == nil {
+			b, err = decodeFile(b)
+		}
+		if err != nil {
+			errs = append(errs, &LoadError{File: fileName, Lang: lang, Err: err})
+			continue
+		}
+
+		fileKeyValues := make(map[string]interface{})
+		if err = unmarshal(b, &fileKeyValues); err != nil {
+			errs = append(errs, &LoadError{File: fileName, Lang: lang, Line: errorLine(b, err), Err: err})
+			continue
+		}
+
+		if fileMeta, ok := fileKeyValues[internal.MetaKey].(internal.MetaMap); ok {
+			delete(fileKeyValues, internal.MetaKey)
+			meta.Merge(fileMeta)
+		}
+
+		for _, key := range flattenKeys("", fileKeyValues) {
+			if prevFileName, ok := keyFiles[key]; ok {
+				switch onDuplicate {
+				case DuplicateKeyError:
+					errs = append(errs, &LoadError{File: fileName, Lang: lang, Err: fmt.Errorf("%w: %s: also defined in %s", ErrDuplicateKey, key, prevFileName)})
+				case DuplicateKeyWarn:
+					log.Printf("i18n: %s: %s: key %q of %s is overridden", lang, fileName, key, prevFileName)
+				}
+			}
+
+			keyFiles[key] = fileName
+		}
+
+		mergeMaps(keyValues, fileKeyValues)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if len(meta) > 0 {
+		keyValues[internal.MetaKey] = meta
+	}
+
+	return keyValues, nil
+}
+
+// unmarshalYAML decodes a YAML locale file,
+// the comments of its keys are stored as their `MessageMeta.Comment`.
+func unmarshalYAML(data []byte, v interface{}) error {
+	if err := yaml.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	if !bytes.Contains(data, []byte("#")) {
+		return nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	meta := make(internal.MetaMap)
+	for _, node := range doc.Content {
+		yamlComments(meta, "", node)
+	}
+
+	if m, ok := v.(*map[string]interface{}); ok && len(meta) > 0 {
+		(*m)[internal.MetaKey] = meta
+	}
+
+	return nil
+}
+
+// yamlComments stores the comments of the keys of the "node" mapping to "meta".
+func yamlComments(meta internal.MetaMap, prefix string, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := prefix + keyNode.Value
+
+		var comment string
+		for _, c := range []string{keyNode.HeadComment, keyNode.LineComment, valueNode.LineComment} {
+			if c = trimYAMLComment(c); c != "" {
+				comment = appendLine(comment, c)
+			}
+		}
+
+		if comment != "" {
+			meta[key] = internal.MessageMeta{Comment: comment}
+		}
+
+		yamlComments(meta, key+".", valueNode)
+	}
+}
+
+// trimYAMLComment returns the text of the "comment" lines, without their "#".
+func trimYAMLComment(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// flattenKeys returns the dotted keys of the "m" values, e.g. "nav.home".
+func flattenKeys(prefix string, m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key, value := range m {
+		if nested, ok := value.(map[string]interface{}); ok {
+			keys = append(keys, flattenKeys(prefix+key+".", nested)...)
+			continue
+		}
+
+		keys = append(keys, prefix+key)
+	}
+
+	return keys
+}
+
+// mergeMaps merges the "src" to "dst", the nested maps are merged too.
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if dstNested, ok := dst[key].(map[string]interface{}); ok {
+				mergeMaps(dstNested, nested)
+				continue
+			}
+		}
+
+		dst[key] = value
+	}
+}
+
+// LoadError is reported by the loaders when a locale file or
+// the translations of a language failed to load.
+// The `New` function and the `I18n.Reload` method return all of them joined,
+// use the `errors.As` to get them.
+type LoadError struct {
+	// File is the locale file's name, if known.
+	File string
+	// Lang is the language code of the translations, e.g. "el-GR".
+	Lang string
+	// Line is the line of the file that failed, if the parser reports it, otherwise zero.
+	Line int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error completes the error interface.
+func (e *LoadError) Error() string {
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s: %s:%d: %v", e.Lang, e.File, e.Line, e.Err)
+	case e.File != "":
+		return fmt.Sprintf("%s: %s: %v", e.Lang, e.File, e.Err)
+	default:
+		return fmt.Sprintf("%s: %v", e.Lang, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+var errorLineRegexp = regexp.MustCompile(`line (\d+)`)
+
+// errorLine returns the line of the "data" which the parser's "err" reports, if any.
+func errorLine(data []byte, err error) int {
+	var (
+		xmlErr    *xml.SyntaxError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		tomlErr   toml.ParseError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		return offsetLine(data, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		return offsetLine(data, typeErr.Offset)
+	case errors.As(err, &tomlErr):
+		return tomlErr.Position.Line
+	case errors.As(err, &xmlErr):
+		return xmlErr.Line
+	}
+
+	// e.g. yaml, ini and properties.
+	if matches := errorLineRegexp.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		line, _ := strconv.Atoi(matches[1])
+		return line
+	}
+
+	return 0
+}
+
+func offsetLine(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	return bytes.Count(data[:offset], []byte{'\n'}) + 1
+}
+
 func unmarshalINI(data []byte, v interface{}) error {
 	f, err := ini.Load(data)
 	if err != nil {
@@ -1,10 +1,22 @@
 package i18n
 
 import (
+	"encoding/binary"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/language"
 )
 
 // go test -vet=off -v
@@ -17,6 +29,66 @@ func TestLoad(t *testing.T) {
 	testLoadAndTrHelper(t, i18N)
 }
 
+func TestLoadGlobs(t *testing.T) {
+	options := DefaultLoaderConfig
+	options.OnDuplicateKey = DuplicateKeyError // the overlapped files are loaded once.
+
+	i18N, err := New(Globs([]string{"./testfiles/en-US/*", "./testfiles/*/*", "./testfiles/el-GR/*.yaml"}, options), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testLoadAndTrHelper(t, i18N)
+}
+
+func TestLoadExclude(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/ui.yml":          {Data: []byte("title: Title")},
+		"locales/en-US/ui.schema.yaml":  {Data: []byte("{not: [valid")},
+		"locales/fr-FR/ui.schema.yaml":  {Data: []byte("{not: [valid")},
+		"locales/de-DE/ui.yml":          {Data: []byte("title: Titel")},
+		"locales/el-GR/ui.yml":          {Data: []byte("title: Τίτλος")},
+		"locales/el-GR/ui.schema.yaml":  {Data: []byte("{not: [valid")},
+		"locales/el-GR/more.schema.yml": {Data: []byte("more: Περισσότερα")},
+	}
+
+	options := DefaultLoaderConfig
+	options.Exclude = []string{"*.schema.yaml", "locales/de-DE/*"}
+
+	loader, err := FS(fileSystem, "./locales/*/*", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var languages []string
+	for _, loc := range i18N.AllLocales() {
+		languages = append(languages, loc.Language())
+	}
+	sort.Strings(languages)
+
+	if expected := []string{"el-GR", "en-US"}; !reflect.DeepEqual(languages, expected) {
+		t.Fatalf("expected languages %v but got %v", expected, languages)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "more"), "Περισσότερα"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	options.Exclude = []string{"["}
+	if loader, err = FS(fileSystem, "./locales/*/*", options); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = New(loader); err == nil {
+		t.Fatalf("expected an invalid exclude pattern error")
+	}
+}
+
 func TestLoadKV(t *testing.T) {
 	m := LangMap{
 		"en-US": Map{
@@ -66,6 +138,31 @@ func TestLoadKV(t *testing.T) {
 	testLoadAndTrHelper(t, i18N)
 }
 
+func TestLoadKVLanguages(t *testing.T) {
+	m := LangMap{
+		"en-US": Map{"title": "Title"},
+		"el-GR": Map{"title": "Τίτλος"},
+		"de-DE": Map{"title": "Titel"},
+		"fr-FR": Map{"title": "Titre"},
+	}
+
+	expected := map[string]string{"en-US": "Title", "el-GR": "Τίτλος", "de-DE": "Titel", "fr-FR": "Titre"}
+
+	// each language is loaded with its own messages,
+	// whatever the order of the map and of the registered languages.
+	for n := 0; n < 10; n++ {
+		i18N, err := New(KV(m), "es-ES", "fr-FR", "de-DE", "el-GR", "en-US")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for lang, title := range expected {
+			if got := i18N.Tr(lang, "title"); got != title {
+				t.Fatalf("[%s] expected %s but got %s", lang, title, got)
+			}
+		}
+	}
+}
 func testLoadAndTrHelper(t *testing.T, i18N *I18n) {
 	t.Helper()
 
@@ -184,72 +281,1854 @@ func TestLoadAbsDirWithPotentialLangCode(t *testing.T) {
 	testLoadAndTrHelper(t, i18N)
 }
 
-func copyDir(src, dest string) error {
-	entries, err := os.ReadDir(src)
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	if err := createIfNotExists(filepath.Join(dir, "en-US"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, "en-US", "welcome.yml")
+	if err := os.WriteFile(fileName, []byte(`welcome: "welcome"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(Watch(dir + "/*/*"))
 	if err != nil {
-		return err
+		t.Fatal(err)
 	}
-	for _, entry := range entries {
-		sourcePath := filepath.Join(src, entry.Name())
-		destPath := filepath.Join(dest, entry.Name())
+	t.Cleanup(func() {
+		i18N.Close()
+	})
 
-		fileInfo, err := os.Stat(sourcePath)
+	got := i18N.Tr("en-US", "welcome")
+	if expected := "welcome"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if err = os.WriteFile(fileName, []byte(`welcome: "welcome back"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "welcome back"
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if got = i18N.Tr("en-US", "welcome"); got == expected {
+			return
+		}
+	}
+
+	t.Fatalf("expected %s but got %s", expected, got)
+}
+
+func TestWatchShared(t *testing.T) {
+	dir := t.TempDir()
+	if err := createIfNotExists(filepath.Join(dir, "en-US"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, "en-US", "welcome.yml")
+	if err := os.WriteFile(fileName, []byte(`welcome: "welcome"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same loader on two instances, each one keeps its own watcher.
+	loader := Watch(dir + "/*/*")
+	instances := make([]*I18n, 2)
+	for idx := range instances {
+		i18N, err := New(loader)
 		if err != nil {
-			return err
+			t.Fatal(err)
 		}
+		t.Cleanup(func() {
+			i18N.Close()
+		})
 
-		switch fileInfo.Mode() & os.ModeType {
-		case os.ModeDir:
-			if err := createIfNotExists(destPath, 0755); err != nil {
-				return err
+		instances[idx] = i18N
+	}
+
+	if err := os.WriteFile(fileName, []byte(`welcome: "welcome back"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "welcome back"
+	for idx, i18N := range instances {
+		got := i18N.Tr("en-US", "welcome")
+		for deadline := time.Now().Add(5 * time.Second); got != expected && time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			got = i18N.Tr("en-US", "welcome")
+		}
+
+		if got != expected {
+			t.Fatalf("[%d] expected %s but got %s", idx, expected, got)
+		}
+	}
+
+	// a manual reload after Close does not watch again.
+	i18N := instances[0]
+	if err := i18N.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := i18N.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(fileName, []byte(`welcome: "welcome again"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * watchDelay)
+	if got, expected := i18N.Tr("en-US", "welcome"), "welcome back"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]map[string]interface{}{
+		"en": {
+			"hello": "Hello {{.Name}}",
+			"nav":   map[string]string{"home": "Home"},
+			"cart": map[string]map[string]string{
+				"items": {"one": "%d item", "other": "%d items"},
+			},
+			"year":  2024,
+			"draft": false,
+		},
+		"el": {"hello": "Γειά {{.Name}}"},
+	}
+
+	i18N, err := New(FromMap(m), "en", "el")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en", "hello", []interface{}{Map{"Name": "kataras"}}, "Hello kataras"},
+		{"el", "hello", []interface{}{Map{"Name": "kataras"}}, "Γειά kataras"},
+		{"el", "nav.home", nil, "Home"},
+		{"en", "cart.items", []interface{}{PluralCount(2)}, "2 items"},
+		{"en", "year", nil, "2024"},
+		{"en", "draft", nil, "false"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	// the KV loader accepts only the Map dictionaries.
+	if _, err = New(KV(m), "en", "el"); err == nil {
+		t.Fatalf("expected an error of the map[string]string value")
+	}
+}
+
+func TestKeyResolution(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/ui.yml": &fstest.MapFile{Data: []byte(`"nav.home": "Flat Home"
+"nav.more.what": "Flat What"
+nav:
+  home: "Nested Home"
+  "more.what": "Half What"
+  more:
+    what: "Nested What"
+  about: "About"
+`)},
+		"locales/en-US/extra.json": &fstest.MapFile{Data: []byte(`{"footer.links": "Flat Links"}`)},
+		"locales/en-US/footer.yml": &fstest.MapFile{Data: []byte("footer:\n  links: \"Nested Links\"\n")},
+	}
+
+	tests := []struct {
+		resolution KeyResolution
+		expected   map[string]string
+	}{
+		{KeyResolutionFlat, map[string]string{
+			"nav.home":      "Flat Home",
+			"nav.more.what": "Flat What",
+			"nav.about":     "About",
+			"footer.links":  "Flat Links",
+		}},
+		{KeyResolutionNested, map[string]string{
+			"nav.home":      "Nested Home",
+			"nav.more.what": "Nested What",
+			"nav.about":     "About",
+			"footer.links":  "Nested Links",
+		}},
+	}
+
+	for _, tt := range tests {
+		options := DefaultLoaderConfig
+		options.KeyResolution = tt.resolution
+
+		// the result should not depend on the map iteration order.
+		for n := 0; n < 10; n++ {
+			loader, err := FS(fileSystem, "./locales/*/*", options)
+			if err != nil {
+				t.Fatal(err)
 			}
-			if err := copyDir(sourcePath, destPath); err != nil {
-				return err
+
+			i18N, err := New(loader, "en-US")
+			if err != nil {
+				t.Fatal(err)
 			}
-		default:
-			if err := copyFile(sourcePath, destPath); err != nil {
-				return err
+
+			for key, expected := range tt.expected {
+				if got := i18N.Tr("en-US", key); got != expected {
+					t.Fatalf("[%d:%s] expected %q but got %q", tt.resolution, key, expected, got)
+				}
 			}
 		}
+	}
+}
 
-		isSymlink := fileInfo.Mode()&os.ModeSymlink != 0
-		if !isSymlink {
-			if err := os.Chmod(destPath, fileInfo.Mode()); err != nil {
-				return err
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	if err := createIfNotExists(filepath.Join(dir, "en-US"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fileName := filepath.Join(dir, "en-US", "welcome.yml")
+	if err := os.WriteFile(fileName, []byte(`welcome: "welcome"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loader, err := FS(os.DirFS(dir), "*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(fileName, []byte(`welcome: "welcome back"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := i18N.Tr("en-US", "welcome")
+	if expected := "welcome back"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// test that a failed reload keeps the previous translations.
+	if err = os.WriteFile(fileName, []byte(`welcome: [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.Reload(); err == nil {
+		t.Fatalf("expected an error on invalid file")
+	}
+
+	got = i18N.Tr("en-US", "welcome")
+	if expected := "welcome back"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestReloadGlobsNewFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := createIfNotExists(filepath.Join(dir, "en-US"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "en-US", "welcome.yml"), []byte(`welcome: "welcome"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(Globs([]string{dir + "/*/*.yml", dir + "/*/*.json"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i18N.Exists("en-US", "goodbye") {
+		t.Fatalf("expected goodbye key to be missing before reload")
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "en-US", "goodbye.json"), []byte(`{"goodbye": "goodbye"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("en-US", "goodbye"), "goodbye"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic on a malformed pattern")
+		}
+	}()
+
+	Globs([]string{dir + "/*/*", "["})
+}
+
+func TestReloadConcurrent(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"welcome": "welcome"},
+		"el-GR": Map{"welcome": "καλώς ήρθες"},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	reloaded := make(chan struct{})
+	go func() {
+		defer close(reloaded)
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if err := i18N.Reload(); err != nil {
+				t.Error(err)
+				return
 			}
 		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, lang := range []string{"en-US", "el-GR", "el", "de-DE"} {
+		wg.Add(1)
+		go func(lang string) {
+			defer wg.Done()
+
+			for n := 0; n < 1000; n++ {
+				if got := i18N.Tr(lang, "welcome"); got == "" {
+					t.Errorf("[%s] expected a translation while reloading", lang)
+					return
+				}
+			}
+		}(lang)
 	}
 
-	return nil
+	wg.Wait()
+	close(done)
+	<-reloaded
 }
 
-func copyFile(srcFile, dstFile string) error {
-	out, err := os.Create(dstFile)
+func TestLoadProperties(t *testing.T) {
+	dir := t.TempDir()
+	contents := `# comment
+! another comment
+nav.home = Home
+nav.more:More \
+    items
+key\=with\:separators value
+unicode = \u0393\u03b5\u03b9\u03ac
+emoji = \uD83D\uDE00
+hello = Hello %s
+`
+	if err := os.WriteFile(filepath.Join(dir, "messages_en.properties"), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(Glob(dir + "/*.properties"))
 	if err != nil {
-		return err
+		t.Fatal(err)
 	}
-	defer out.Close()
 
-	in, err := os.Open(srcFile)
+	tests := []struct {
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"nav.home", nil, "Home"},
+		{"nav.more", nil, "More items"},
+		{"key=with:separators", nil, "value"},
+		{"unicode", nil, "Γειά"},
+		{"emoji", nil, "😀"},
+		{"hello", []interface{}{"kataras"}, "Hello kataras"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr("en", tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestLoadMeta(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/messages.po": &fstest.MapFile{Data: []byte(`msgid ""
+msgstr ""
+"Language: en-US\n"
+
+# Keep it short.
+#. The greeting of the home page.
+#: main.go:10
+msgid "hello"
+msgstr "Hello"
+
+msgid "bye"
+msgstr "Bye"
+`)},
+		"locales/en-US/ui.yml": &fstest.MapFile{Data: []byte(`title: Title
+
+nav:
+  # The link to the more page.
+  more: More # a button
+  home: Home
+`)},
+		"locales/en-US/goi18n.json": &fstest.MapFile{Data: []byte(`{
+  "welcome": {"description": "The welcome message", "other": "Welcome"},
+  "files": {"description": "The files count", "one": "%d file", "other": "%d files"}
+}`)},
+	}
+
+	loader, err := FS(fileSystem, "./locales/*/*")
 	if err != nil {
-		return err
+		t.Fatal(err)
 	}
-	defer in.Close()
 
-	_, err = io.Copy(out, in)
+	i18N, err := New(Chain(loader, KV(LangMap{"en-US": Map{"extra": "Extra"}})), "en-US")
 	if err != nil {
-		return err
+		t.Fatal(err)
 	}
 
-	return nil
-}
+	loc := i18N.GetLocaleByTag(language.MustParse("en-US"))
 
-func createIfNotExists(dir string, perm os.FileMode) error {
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, perm); err != nil {
-			return err
+	tests := []struct {
+		key      string
+		expected MessageMeta
+	}{
+		{"hello", MessageMeta{Description: "The greeting of the home page.", Comment: "Keep it short."}},
+		{"bye", MessageMeta{}},
+		{"nav.more", MessageMeta{Comment: "The link to the more page.\na button"}},
+		{"nav.home", MessageMeta{}},
+		{"welcome", MessageMeta{Description: "The welcome message"}},
+		{"files", MessageMeta{Description: "The files count"}},
+		{"extra", MessageMeta{}},
+		{"missing", MessageMeta{}},
+	}
+
+	for _, tt := range tests {
+		if got := loc.Meta(tt.key); got != tt.expected {
+			t.Fatalf("[%s] expected %#v but got %#v", tt.key, tt.expected, got)
 		}
 	}
 
-	return nil
+	if got, expected := i18N.Tr("en-US", "welcome"), "Welcome"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if got, expected := i18N.Tr("en-US", "files", PluralCount(2)), "2 files"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if got, expected := i18N.Tr("en-US", "nav.more"), "More"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestLoadGettext(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"en-US/messages.po": `msgid ""
+msgstr ""
+"Language: en-US\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "hello"
+msgstr "Hello %s"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d file"
+msgstr[1] "%d files"
+
+msgid "only.default"
+msgstr "only on default"
+
+msgid "Post"
+msgstr "Post"
+
+msgctxt "verb"
+msgid "Post"
+msgstr "Publish"
+`,
+		"ru/messages.po": `# Russian translation.
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && "
+"n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#: main.go:10
+msgid "hello"
+msgstr "Привет "
+"%s"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+#, fuzzy
+msgid "only.default"
+msgstr "не переведено"
+
+msgid "Post"
+msgstr "Запись"
+
+#. The button which publishes a post.
+msgctxt "verb"
+msgid "Post"
+msgstr "Опубликовать"
+`,
+	}
+
+	for name, contents := range files {
+		fileName := filepath.Join(dir, name)
+		if err := createIfNotExists(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	i18N, err := New(Glob(dir+"/*/*.po"), "en-US", "ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "hello", []interface{}{"kataras"}, "Hello kataras"},
+		{"ru", "hello", []interface{}{"kataras"}, "Привет kataras"},
+		{"en-US", "%d file", []interface{}{PluralCount(1)}, "1 file"},
+		{"en-US", "%d file", []interface{}{PluralCount(2)}, "2 files"},
+		{"ru", "%d file", []interface{}{PluralCount(1)}, "1 файл"},
+		{"ru", "%d file", []interface{}{PluralCount(3)}, "3 файла"},
+		{"ru", "%d file", []interface{}{PluralCount(5)}, "5 файлов"},
+		{"ru", "%d file", []interface{}{PluralCount(21)}, "21 файл"},
+		{"ru", "%d file", []interface{}{PluralCount(12)}, "12 файлов"},
+		// fuzzy entries fallback to the default language.
+		{"ru", "only.default", nil, "only on default"},
+		{"ru", "Post", nil, "Запись"},
+		{"ru", "Post@verb", nil, "Опубликовать"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	ru := i18N.GetLocaleByTag(language.Russian)
+	contextTests := []struct {
+		ctx      string
+		expected string
+	}{
+		{"verb", "Опубликовать"},
+		{"", "Запись"},
+		{"noun", "Запись"}, // not found, the message without context.
+	}
+
+	for _, tt := range contextTests {
+		if got := ru.GetMessageContext(tt.ctx, "Post"); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.ctx, tt.expected, got)
+		}
+	}
+
+	expectedMeta := MessageMeta{Description: "The button which publishes a post.", Context: "verb"}
+	if got := ru.Meta("Post@verb"); got != expectedMeta {
+		t.Fatalf("expected %#+v but got %#+v", expectedMeta, got)
+	}
+}
+
+func TestParseMO(t *testing.T) {
+	// originals and translations sorted by original, see the MO file format.
+	originals := []string{"", "%d file\x00%d files", "hello"}
+	translations := []string{"Plural-Forms: nplurals=2; plural=(n != 1);\n", "%d αρχείο\x00%d αρχεία", "Γειά %s"}
+
+	var (
+		n           = uint32(len(originals))
+		headerSize  = uint32(28)
+		tablesSize  = n * 8 * 2
+		stringsData []byte
+		tables      = make([]byte, tablesSize)
+	)
+
+	for i, s := range append(originals, translations...) {
+		offset := headerSize + tablesSize + uint32(len(stringsData))
+		binary.LittleEndian.PutUint32(tables[i*8:], uint32(len(s)))
+		binary.LittleEndian.PutUint32(tables[i*8+4:], offset)
+		stringsData = append(stringsData, s...)
+		stringsData = append(stringsData, 0)
+	}
+
+	header := make([]byte, headerSize)
+	binary.LittleEndian.PutUint32(header[0:], 0x950412de)
+	binary.LittleEndian.PutUint32(header[8:], n)
+	binary.LittleEndian.PutUint32(header[12:], headerSize)
+	binary.LittleEndian.PutUint32(header[16:], headerSize+n*8)
+
+	data := append(append(header, tables...), stringsData...)
+
+	i18N, err := New(Assets(func() []string { return []string{"el-GR/messages.mo"} }, func(string) ([]byte, error) {
+		return data, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el", "hello", "kataras"), "Γειά kataras"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if got, expected := i18N.Tr("el", "%d file", PluralCount(2)), "2 αρχεία"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestChain(t *testing.T) {
+	core := KV(LangMap{
+		"en-US": Map{"title": "Core Title", "hello": "Hello %s"},
+		"el-GR": Map{"title": "Τίτλος"},
+	})
+	plugin := KV(LangMap{
+		"en-US": Map{"title": "Plugin Title", "cart.checkout": "checkout - {{.Param}}"},
+	})
+
+	i18N, err := New(Chain(core, plugin), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "title", nil, "Plugin Title"},
+		{"en-US", "hello", []interface{}{"kataras"}, "Hello kataras"},
+		{"en-US", "cart.checkout", []interface{}{map[string]string{"Param": "all"}}, "checkout - all"},
+		{"el-GR", "title", nil, "Τίτλος"},
+		{"el-GR", "cart.checkout", []interface{}{map[string]string{"Param": "all"}}, "checkout - all"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	if _, err = New(ChainWith(DuplicateKeyError, core, plugin), "en-US", "el-GR"); err == nil {
+		t.Fatalf("expected an error on duplicate key")
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	core := KV(LangMap{
+		"en-US": Map{"title": "Title"},
+	})
+	admin := KV(LangMap{
+		"en-US": Map{"title": "Admin Title", "welcome": "Welcome to {{tr \"title\"}}"},
+	})
+	users := Namespace("admin", Namespace("users", KV(LangMap{
+		"en-US": Map{"title": "Users"},
+	})))
+
+	i18N, err := New(Chain(core, Namespace("admin", admin), users), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"title", "Title"},
+		{"admin.title", "Admin Title"},
+		{"admin.welcome", "Welcome to Admin Title"},
+		{"admin.users.title", "Users"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr("en-US", tt.key); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.key, tt.expected, got)
+		}
+	}
+}
+
+func copyDir(src, dest string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		sourcePath := filepath.Join(src, entry.Name())
+		destPath := filepath.Join(dest, entry.Name())
+
+		fileInfo, err := os.Stat(sourcePath)
+		if err != nil {
+			return err
+		}
+
+		switch fileInfo.Mode() & os.ModeType {
+		case os.ModeDir:
+			if err := createIfNotExists(destPath, 0755); err != nil {
+				return err
+			}
+			if err := copyDir(sourcePath, destPath); err != nil {
+				return err
+			}
+		default:
+			if err := copyFile(sourcePath, destPath); err != nil {
+				return err
+			}
+		}
+
+		isSymlink := fileInfo.Mode()&os.ModeSymlink != 0
+		if !isSymlink {
+			if err := os.Chmod(destPath, fileInfo.Mode()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func copyFile(srcFile, dstFile string) error {
+	out, err := os.Create(dstFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	in, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func createIfNotExists(dir string, perm os.FileMode) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, perm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestLoadFS(t *testing.T) {
+	fileSystems := map[string]fs.FS{
+		"os.DirFS": os.DirFS("./testfiles"),
+		"fstest.MapFS": fstest.MapFS{
+			"en-US/ui.yaml": {Data: []byte(`title: "Title"`)},
+			"el-GR/ui.yaml": {Data: []byte(`title: "Τίτλος"`)},
+		},
+	}
+
+	for name, fileSystem := range fileSystems {
+		loader, err := FS(fileSystem, "./*/*.yaml")
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		i18N, err := New(loader, "en-US", "el-GR")
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		if got, expected := i18N.Tr("el-GR", "title"), "Τίτλος"; got != expected {
+			t.Fatalf("[%s] expected %s but got %s", name, expected, got)
+		}
+	}
+}
+
+func TestLoadDB(t *testing.T) {
+	db := map[string]map[string]interface{}{
+		"en-US": {"title": "Title", "hi": "Hi {{.Name}}"},
+		"el-GR": {"title": "Τίτλος", "nav": map[string]interface{}{"home": "Αρχική"}},
+	}
+
+	loader := DB(func(lang string) (map[string]interface{}, error) {
+		return db[lang], nil
+	}, "en-US", "el-GR")
+
+	i18N, err := New(loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"el-GR", "title", nil, "Τίτλος"},
+		{"el-GR", "nav.home", nil, "Αρχική"},
+		{"en-US", "hi", []interface{}{map[string]string{"Name": "kataras"}}, "Hi kataras"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	db["el-GR"]["title"] = "Νέος τίτλος"
+	if err = i18N.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "title"), "Νέος τίτλος"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+}
+
+func TestLoadLazy(t *testing.T) {
+	var loadedLanguages []string
+	loader := Lazy(func(tag language.Tag) (Map, error) {
+		loadedLanguages = append(loadedLanguages, tag.String())
+		switch tag.String() {
+		case "en-US":
+			return Map{"title": "Title", "hi": "Hi {{.Name}}"}, nil
+		case "el-GR":
+			return Map{"title": "Τίτλος"}, nil
+		default:
+			return nil, nil
+		}
+	}, "en-US", "el-GR", "zh-CN")
+
+	i18N, err := New(loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"en-US"}; !reflect.DeepEqual(loadedLanguages, expected) {
+		t.Fatalf("expected only %v to be loaded but got %v", expected, loadedLanguages)
+	}
+
+	for n := 0; n < 2; n++ {
+		if got, expected := i18N.Tr("el-GR", "title"), "Τίτλος"; got != expected {
+			t.Fatalf("expected %s but got %s", expected, got)
+		}
+	}
+
+	if got, expected := i18N.Tr("el-GR", "hi", map[string]string{"Name": "kataras"}), "Hi kataras"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if expected := []string{"en-US", "el-GR"}; !reflect.DeepEqual(loadedLanguages, expected) {
+		t.Fatalf("expected %v to be loaded once but got %v", expected, loadedLanguages)
+	}
+
+	i18N, err = New(LazyGlob("./testfiles/*/*"), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testLoadAndTrHelper(t, i18N)
+}
+
+func TestLoadLazyWithDefault(t *testing.T) {
+	var loadedLanguages []string
+	loader := Lazy(func(tag language.Tag) (Map, error) {
+		loadedLanguages = append(loadedLanguages, tag.String())
+		switch tag.String() {
+		case "en-US":
+			return Map{"title": "Title"}, nil
+		case "el-GR":
+			return Map{"title": "Τίτλος", "onlyGreek": "Μόνο"}, nil
+		default:
+			return nil, nil
+		}
+	})
+
+	i18N, err := NewWithOptions(loader, WithLanguages("en-US", "el-GR"), WithDefault("el-GR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default language is loaded first, not the first registered one.
+	if expected := []string{"el-GR"}; !reflect.DeepEqual(loadedLanguages, expected) {
+		t.Fatalf("expected only %v to be loaded but got %v", expected, loadedLanguages)
+	}
+
+	if got, expected := i18N.Tr("en-US", "onlyGreek"), "Μόνο"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if expected := []string{"el-GR", "en-US"}; !reflect.DeepEqual(loadedLanguages, expected) {
+		t.Fatalf("expected %v to be loaded but got %v", expected, loadedLanguages)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"en-US/ui.yaml":    "title: \"Title\"\n",
+		"en-US/user.yaml":  "hi: \"Hi\"\nhello: [\n",
+		"el-GR/other.json": "{\n  \"title\": \"Τίτλος\",\n  \"hi\" \"Γειά\"\n}",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := New(Glob(filepath.Join(dir, "*", "*")), "en-US", "el-GR")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("expected a LoadError but got %T: %v", err, err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined errors but got %T", err)
+	}
+
+	expected := map[string]int{
+		"el-GR/other.json": 3,
+		"en-US/user.yaml":  2,
+	}
+
+	errs := joined.Unwrap()
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors but got %d: %v", len(expected), len(errs), err)
+	}
+
+	for _, err := range errs {
+		if !errors.As(err, &loadErr) {
+			t.Fatalf("expected a LoadError but got %T: %v", err, err)
+		}
+
+		file := filepath.ToSlash(strings.TrimPrefix(loadErr.File, dir+string(filepath.Separator)))
+		if line, ok := expected[file]; !ok || line != loadErr.Line {
+			t.Fatalf("unexpected error: %s (line %d)", loadErr, loadErr.Line)
+		}
+		if lang := filepath.Dir(file); loadErr.Lang != lang {
+			t.Fatalf("expected language %s but got %s", lang, loadErr.Lang)
+		}
+	}
+}
+
+func TestLoadStrictTemplates(t *testing.T) {
+	m := LangMap{
+		"en-US": Map{
+			"hi":    "Hi {{.Name}",
+			"bye":   "Bye {{.Name",
+			"title": "Title",
+		},
+	}
+
+	i18N, err := New(KV(m), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("en-US", "hi"), "Hi {{.Name}"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	options := DefaultLoaderConfig
+	options.StrictTemplates = true
+
+	_, err = New(KV(m, options), "en-US")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) || loadErr.Lang != "en-US" {
+		t.Fatalf("expected a LoadError of en-US but got %T: %v", err, err)
+	}
+
+	for _, key := range []string{"en-US:hi", "en-US:bye"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Fatalf("expected the error to report the %s but got %v", key, err)
+		}
+	}
+}
+
+func TestLoadDelims(t *testing.T) {
+	options := DefaultLoaderConfig
+	options.Left = "[["
+	options.Right = "]]"
+	options.Delims = map[string]Delims{
+		"zh":    {Left: "{{", Right: "}}"},
+		"el-GR": {Left: "<%", Right: "%>"},
+	}
+
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"hi": "Hi [[.Name]] {{.Name}}"},
+		"zh-CN": Map{"hi": "你好 {{.Name}} [[.Name]]"},
+		"el-GR": Map{"hi": "Γειά σου <%.Name%>"},
+	}, options), "en-US", "zh-CN", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]string{"Name": "kataras"}
+	tests := []struct {
+		lang     string
+		expected string
+	}{
+		{"en-US", "Hi kataras {{.Name}}"},
+		{"zh-CN", "你好 kataras [[.Name]]"},
+		{"el-GR", "Γειά σου kataras"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, "hi", data); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.lang, tt.expected, got)
+		}
+	}
+}
+
+func TestLoadBuiltinFuncs(t *testing.T) {
+	options := DefaultLoaderConfig
+	options.Funcs = func(*Locale) template.FuncMap {
+		return template.FuncMap{
+			"lower": func(s string) string { return "custom " + s },
+		}
+	}
+
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"title": "{{title .Name}}",
+			"upper": "{{upper .Name}}",
+			"lower": "{{lower .Name}}",
+			"trim":  "[{{trim .Name}}]",
+		},
+		"tr-TR": Map{
+			"upper": "{{upper .Name}}",
+		},
+	}, options), "en-US", "tr-TR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		name     string
+		expected string
+	}{
+		{"en-US", "title", "gerasimos maropoulos", "Gerasimos Maropoulos"},
+		{"en-US", "upper", "istanbul", "ISTANBUL"},
+		{"tr-TR", "upper", "istanbul", "İSTANBUL"},
+		{"en-US", "lower", "KATARAS", "custom KATARAS"},
+		{"en-US", "trim", "  kataras ", "[kataras]"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, map[string]string{"Name": tt.name}); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestLoadFormatFuncs(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"total": "{{number .Count}} items, {{currency .Price \"EUR\"}}"},
+		"el-GR": Map{"total": "{{number .Count}} αντικείμενα, {{currency .Price \"EUR\"}}"},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]interface{}{"Count": 1234, "Price": 1234.56}
+	tests := []struct {
+		lang             string
+		expected         string
+		expectedNumber   string
+		expectedCurrency string
+	}{
+		{"en-US", "1,234 items, € 1,234.56", "1,234.5", "$ 10.00"},
+		{"el-GR", "1.234 αντικείμενα, € 1.234,56", "1.234,5", "$ 10,00"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, "total", data); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.lang, tt.expected, got)
+		}
+
+		_, index, _ := i18N.TryMatchString(tt.lang)
+		loc := i18N.getLocaleByIndex(index)
+		if got := loc.FormatNumber(1234.5); got != tt.expectedNumber {
+			t.Fatalf("[%s] expected number %s but got %s", tt.lang, tt.expectedNumber, got)
+		}
+		if got := loc.FormatCurrency(10, "USD"); got != tt.expectedCurrency {
+			t.Fatalf("[%s] expected currency %s but got %s", tt.lang, tt.expectedCurrency, got)
+		}
+	}
+}
+
+func TestLoadFormatDate(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{"joined": "joined on {{date .CreatedAt \"long\"}}"},
+		"el-GR": Map{"joined": "εγγράφηκε στις {{date .CreatedAt \"long\"}}"},
+		"es-ES": Map{"joined": "{{date .CreatedAt \"long\"}}"},
+		"ja-JP": Map{"joined": "{{date .CreatedAt \"long\"}}"},
+	}), "en-US", "el-GR", "es-ES", "ja-JP")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := time.Date(2024, time.January, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		lang           string
+		expected       string
+		expectedShort  string
+		expectedMedium string
+	}{
+		{"en-US", "joined on January 5, 2024", "1/5/24", "Jan 5, 2024"},
+		{"el-GR", "εγγράφηκε στις 5 Ιανουαρίου 2024", "5/1/24", "5 Ιαν 2024"},
+		{"es-ES", "5 de enero de 2024", "5/1/24", "5 ene 2024"},
+		{"ja-JP", "2024-01-05", "2024-01-05", "2024-01-05"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, "joined", map[string]interface{}{"CreatedAt": createdAt}); got != tt.expected {
+			t.Fatalf("[%s] expected %s but got %s", tt.lang, tt.expected, got)
+		}
+
+		_, index, _ := i18N.TryMatchString(tt.lang)
+		loc := i18N.getLocaleByIndex(index)
+		if got := loc.FormatDate(createdAt, "short"); got != tt.expectedShort {
+			t.Fatalf("[%s] expected short date %s but got %s", tt.lang, tt.expectedShort, got)
+		}
+		if got := loc.FormatDate(createdAt, "medium"); got != tt.expectedMedium {
+			t.Fatalf("[%s] expected medium date %s but got %s", tt.lang, tt.expectedMedium, got)
+		}
+	}
+}
+
+func TestLoadReferences(t *testing.T) {
+	i18N, err := New(KV(LangMap{
+		"en-US": Map{
+			"brand":  Map{"name": "Iris"},
+			"footer": `{{tr "brand.name"}} © 2024`,
+			"legal":  `{{tr "footer"}}, all rights reserved`,
+			"hello":  `Hello {{tr "brand.name"}}`,
+			"a":      `{{tr "b"}}`,
+			"b":      `{{tr "a"}}`,
+		},
+		"el-GR": Map{
+			"hello": `Γειά σου {{tr "brand.name"}}`,
+		},
+	}), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"en-US", "footer", "Iris © 2024"},
+		{"en-US", "legal", "Iris © 2024, all rights reserved"},
+		{"el-GR", "hello", "Γειά σου Iris"}, // falls back to the default language.
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	if _, err = i18N.TrError("en-US", "a"); err == nil || !strings.Contains(err.Error(), "cyclic reference") {
+		t.Fatalf("expected cyclic reference error but got %v", err)
+	}
+}
+
+func TestLoadCSV(t *testing.T) {
+	dir := t.TempDir()
+
+	csvFile := filepath.Join(dir, "translations.csv")
+	contents := "key,en-US,el-GR\n" +
+		"title,Title,Τίτλος\n" +
+		"nav.home,Home,Αρχική\n" +
+		"hi,\"Hi {{.Name}}, welcome\",\"Γειά σου {{.Name}}\"\n" +
+		"multiline,\"line 1\nline 2\",\n" +
+		"only.default,value\n"
+	if err := os.WriteFile(csvFile, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(CSV(csvFile), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"en-US", "title", "Title"},
+		{"el-GR", "title", "Τίτλος"},
+		{"el-GR", "nav.home", "Αρχική"},
+		{"en-US", "hi", "Hi kataras, welcome"},
+		{"el-GR", "hi", "Γειά σου kataras"},
+		{"en-US", "multiline", "line 1\nline 2"},
+		{"el-GR", "multiline", "line 1\nline 2"}, // empty cell, fallback to the default language.
+		{"el-GR", "only.default", "value"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, Map{"Name": "kataras"}); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	tsvFile := filepath.Join(dir, "translations.tsv")
+	if err = os.WriteFile(tsvFile, []byte("key\ten\tel\ntitle\tTitle, TSV\tΤίτλος\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err = New(CSV(tsvFile), "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("en-US", "title"), "Title, TSV"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	if err = os.WriteFile(csvFile, []byte("key,en-US\ntitle,Title\nhi,\"Hi\" there\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var loadErr *LoadError
+	if _, err = New(CSV(csvFile)); !errors.As(err, &loadErr) {
+		t.Fatalf("expected a LoadError but got %v", err)
+	}
+	if loadErr.Line != 3 {
+		t.Fatalf("expected error on line 3 but got %d: %v", loadErr.Line, err)
+	}
+}
+
+func TestLoadXLIFF(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"xliff/en-US.xliff": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en-US">
+  <file id="f1">
+    <unit id="title"><segment><source>Title</source><target>Title</target></segment></unit>
+    <unit id="hi"><segment><source>Hi {{.Name}}</source><target>Hi {{.Name}}</target></segment></unit>
+    <group id="nav">
+      <unit id="home"><segment><source>Home</source><target>Home</target></segment></unit>
+      <unit id="new"><segment><source>New</source><target>New</target></segment></unit>
+    </group>
+  </file>
+</xliff>`)},
+		"xliff/messages.xlf": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en-US" trgLang="el-GR">
+  <file id="f1">
+    <unit id="title"><segment state="final"><source>Title</source><target>Τίτλος</target></segment></unit>
+    <unit id="hi">
+      <segment state="translated"><source>Hi </source><target>Γειά σου </target></segment>
+      <segment state="reviewed"><source>{{.Name}}</source><target>{{.Name}}</target></segment>
+    </unit>
+    <group id="nav">
+      <unit id="home"><segment state="final"><source>Home</source><target>Αρχική</target></segment></unit>
+      <unit id="new"><segment state="initial"><source>New</source><target>Νέο</target></segment></unit>
+    </group>
+  </file>
+</xliff>`)},
+	}
+
+	loader, err := FS(fileSystem, "xliff/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"en-US", "title", "Title"},
+		{"el-GR", "title", "Τίτλος"},
+		{"el-GR", "hi", "Γειά σου kataras"},
+		{"el-GR", "nav.home", "Αρχική"},
+		{"el-GR", "nav.new", "New"}, // needs translation, fallback to the default language.
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, Map{"Name": "kataras"}); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %s but got %s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	fileSystem["xliff/messages.xlf"] = &fstest.MapFile{Data: []byte("<xliff version=\"2.0\" trgLang=\"el-GR\">\n<file>\n</xliff>")}
+	if loader, err = FS(fileSystem, "xliff/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	var loadErr *LoadError
+	if _, err = New(loader, "en-US", "el-GR"); !errors.As(err, &loadErr) {
+		t.Fatalf("expected a LoadError but got %v", err)
+	}
+	if loadErr.Line != 3 {
+		t.Fatalf("expected error on line 3 but got %d: %v", loadErr.Line, err)
+	}
+}
+
+func TestLoadLangFromDir(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/buttons.yml":    &fstest.MapFile{Data: []byte("ok: OK")},
+		"locales/el-GR/buttons.en.yml": &fstest.MapFile{Data: []byte("ok: Εντάξει")},
+	}
+
+	options := DefaultLoaderConfig
+	options.LangFromDir = true
+
+	loader, err := FS(fileSystem, "locales/*/*", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "ok"), "Εντάξει"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+	if got, expected := i18N.Tr("en-US", "ok"), "OK"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// without LangFromDir the file name's "en" wins.
+	if loader, err = FS(fileSystem, "locales/*/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	if i18N, err = New(loader, "en-US", "el-GR"); err != nil {
+		t.Fatal(err)
+	}
+
+	if i18N.Exists("el-GR", "ok") {
+		t.Fatalf("expected the el-GR/buttons.en.yml to be loaded as en-US")
+	}
+}
+
+func TestLoadOnlyRegisteredLanguages(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/no.yml": &fstest.MapFile{Data: []byte("ok: OK")},
+		"locales/el-GR/id.yml": &fstest.MapFile{Data: []byte("ok: Εντάξει")},
+	}
+
+	options := DefaultLoaderConfig
+	options.OnlyRegisteredLanguages = true
+
+	loader, err := FS(fileSystem, "locales/*/*", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := i18N.Tr("el-GR", "ok"), "Εντάξει"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+	if got, expected := i18N.Tr("en-US", "ok"), "OK"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	// without OnlyRegisteredLanguages the file names are parsed as Norwegian and Indonesian.
+	if loader, err = FS(fileSystem, "locales/*/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	if i18N, err = New(loader, "en-US", "el-GR"); err != nil {
+		t.Fatal(err)
+	}
+
+	if i18N.Exists("en-US", "ok") || i18N.Exists("el-GR", "ok") {
+		t.Fatalf("expected the files to be skipped")
+	}
+}
+
+func TestLoadOrder(t *testing.T) {
+	files := map[string][]byte{
+		"locales/en-US/b.yml": []byte("title: B\nb: B"),
+		"locales/en-US/a.yml": []byte("title: A\na: A"),
+	}
+
+	// unordered names, e.g. of a platform-dependent listing.
+	assetNames := func() []string { return []string{"locales/en-US/b.yml", "locales/en-US/a.yml"} }
+	asset := func(name string) ([]byte, error) { return files[name], nil }
+
+	for n := 0; n < 5; n++ {
+		i18N, err := New(Assets(assetNames, asset), "en-US")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for key, expected := range map[string]string{"title": "B", "a": "A", "b": "B"} {
+			if got := i18N.Tr("en-US", key); got != expected {
+				t.Fatalf("[%s] expected %s but got %s", key, expected, got)
+			}
+		}
+	}
+}
+
+func TestLoadOnDuplicateKey(t *testing.T) {
+	files := map[string][]byte{
+		"locales/en-US/a.yml": []byte("title: A\nnav:\n  home: Home"),
+		"locales/en-US/b.yml": []byte("title: B\nnav:\n  about: About"),
+	}
+	assetNames := func() []string { return []string{"locales/en-US/a.yml", "locales/en-US/b.yml"} }
+	asset := func(name string) ([]byte, error) { return files[name], nil }
+
+	i18N, err := New(Assets(assetNames, asset), "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the nested values of different files are merged.
+	for key, expected := range map[string]string{"title": "B", "nav.home": "Home", "nav.about": "About"} {
+		if got := i18N.Tr("en-US", key); got != expected {
+			t.Fatalf("[%s] expected %s but got %s", key, expected, got)
+		}
+	}
+
+	options := DefaultLoaderConfig
+	options.OnDuplicateKey = DuplicateKeyError
+
+	_, err = New(Assets(assetNames, asset, options), "en-US")
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey but got %v", err)
+	}
+
+	var loadErr *LoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("expected a LoadError but got %v", err)
+	}
+	if loadErr.File != "locales/en-US/b.yml" || !strings.Contains(err.Error(), "title") || !strings.Contains(err.Error(), "locales/en-US/a.yml") {
+		t.Fatalf("expected the key and both files on the error but got %v", err)
+	}
+}
+
+func TestLoadEscapedDotDuplicateKey(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/files.yml": &fstest.MapFile{Data: []byte(`files:
+  config\.yaml: "The configuration file"
+  config:
+    yaml: "The YAML configuration"
+    json: "The JSON configuration"
+`)},
+	}
+
+	options := DefaultLoaderConfig
+	options.OnDuplicateKey = DuplicateKeyError
+
+	loader, err := FS(fileSystem, "./locales/*/*", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = New(loader, "en-US")
+	if !errors.Is(err, ErrDuplicateKey) {
+		t.Fatalf("expected ErrDuplicateKey but got %v", err)
+	}
+	if !strings.Contains(err.Error(), "files.config.yaml") {
+		t.Fatalf("expected the key on the error but got %v", err)
+	}
+
+	// the escaped key wins by default.
+	loader, err = FS(fileSystem, "./locales/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]string{
+		`files.config\.yaml`: "The configuration file",
+		"files.config.json":  "The JSON configuration",
+	} {
+		if got := i18N.Tr("en-US", key); got != expected {
+			t.Fatalf("[%s] expected %q but got %q", key, expected, got)
+		}
+	}
+}
+
+func TestLoadFluent(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/main.ftl": &fstest.MapFile{Data: []byte(`# Simple things are simple.
+-brand-name = Iris
+hello = Hello, { $name }!
+about = About { -brand-name }
+footer = { about }, { $year }
+
+login = Log in
+    .title = Click to log in to { -brand-name }
+
+emails = { $count ->
+    [0] No emails
+    [one] One email
+   *[other] { $count } emails
+}
+
+shared = { $gender ->
+    [male] He shared
+    [female] She shared
+   *[other] They shared
+} a photo.
+
+multiline =
+    First line
+    second line
+escaped = { "{" } literal { 42 }
+user = Hi { $user-name }
+`)},
+		"locales/el-GR/main.ftl": &fstest.MapFile{Data: []byte(`hello = Γειά σου, { $name }!
+emails = { $count ->
+    [one] Ένα email
+   *[other] { $count } emails
+}
+`)},
+	}
+
+	loader, err := FS(fileSystem, "locales/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		data     Map
+		expected string
+	}{
+		{"en-US", "hello", Map{"name": "kataras"}, "Hello, kataras!"},
+		{"el-GR", "hello", Map{"name": "kataras"}, "Γειά σου, kataras!"},
+		{"en-US", "about", nil, "About Iris"},
+		{"en-US", "footer", Map{"year": 2024}, "About Iris, 2024"},
+		{"en-US", "login", nil, "Log in"},
+		{"en-US", "login.title", nil, "Click to log in to Iris"},
+		{"en-US", "emails", Map{"count": 0}, "No emails"},
+		{"en-US", "emails", Map{"count": 1}, "One email"},
+		{"en-US", "emails", Map{"count": 5}, "5 emails"},
+		{"el-GR", "emails", Map{"count": 1}, "Ένα email"},
+		{"en-US", "shared", Map{"gender": "female"}, "She shared a photo."},
+		{"en-US", "shared", Map{"gender": "unknown"}, "They shared a photo."},
+		{"en-US", "multiline", nil, "First line\nsecond line"},
+		{"en-US", "escaped", nil, "{ literal 42"},
+		{"en-US", "user", Map{"user-name": "kataras"}, "Hi kataras"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.data); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	fileSystem["locales/el-GR/main.ftl"] = &fstest.MapFile{Data: []byte("hello = Γειά\nemails = { $count ->\n    [one] Ένα email\n}\n")}
+	if loader, err = FS(fileSystem, "locales/*/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	var loadErr *LoadError
+	if _, err = New(loader, "en-US", "el-GR"); !errors.As(err, &loadErr) {
+		t.Fatalf("expected a LoadError but got %v", err)
+	}
+	if loadErr.Line != 2 {
+		t.Fatalf("expected error on line 2 but got %d: %v", loadErr.Line, err)
+	}
+}
+
+func TestLoadJSONC(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/ui.jsonc": &fstest.MapFile{Data: []byte(`{
+  // the title of the home page.
+  "title": "Home",
+  "url": "https://example.com/*not-a-comment*/", /* a link */
+  "nav": {
+    "more": "More \"//\" items",
+    "less": {"one": "%d less", "other": "%d less",},
+  },
+}`)},
+		"locales/el-GR/ui.json5": &fstest.MapFile{Data: []byte(`/*
+ * Greek.
+ */
+{"title": "Αρχική",}`)},
+	}
+
+	loader, err := FS(fileSystem, "./locales/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"en-US", "title", "Home"},
+		{"en-US", "url", "https://example.com/*not-a-comment*/"},
+		{"en-US", "nav.more", `More "//" items`},
+		{"el-GR", "title", "Αρχική"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	if got, expected := i18N.Tr("en-US", "nav.less", PluralCount(2)), "2 less"; got != expected {
+		t.Fatalf("expected %s but got %s", expected, got)
+	}
+
+	fileSystem["locales/en-US/broken.jsonc"] = &fstest.MapFile{Data: []byte("{\n\"a\": \"b\" /* unterminated\n}")}
+	if loader, err = FS(fileSystem, "./locales/*/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = New(loader, "en-US", "el-GR"); err == nil {
+		t.Fatalf("expected an unterminated comment error")
+	}
+}
+
+func TestLoadBOM(t *testing.T) {
+	utf16LE := []byte{0xFF, 0xFE}
+	for _, r := range "title: Αρχική\n" {
+		utf16LE = append(utf16LE, byte(r), byte(r>>8))
+	}
+
+	fileSystem := fstest.MapFS{
+		"locales/en-US/ui.yml":  &fstest.MapFile{Data: []byte("\xEF\xBB\xBFtitle: Home\nnav: More\n")},
+		"locales/de-DE/ui.json": &fstest.MapFile{Data: []byte("\xEF\xBB\xBF{\"title\": \"Startseite\"}")},
+		"locales/el-GR/ui.yml":  &fstest.MapFile{Data: utf16LE},
+	}
+
+	loader, err := FS(fileSystem, "./locales/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "de-DE", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		expected string
+	}{
+		{"en-US", "title", "Home"},
+		{"en-US", "nav", "More"},
+		{"de-DE", "title", "Startseite"},
+		{"el-GR", "title", "Αρχική"},
+	}
+
+	for _, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key); got != tt.expected {
+			t.Fatalf("[%s:%s] expected %q but got %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	fileSystem["locales/el-GR/ui.yml"] = &fstest.MapFile{Data: append(utf16LE, 'x')}
+	if loader, err = FS(fileSystem, "./locales/*/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	var loadErr *LoadError
+	if _, err = New(loader, "en-US", "de-DE", "el-GR"); !errors.As(err, &loadErr) {
+		t.Fatalf("expected a load error of an odd length UTF-16 file but got: %v", err)
+	}
+}
+
+func TestLoadAndroidStrings(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"res/values-en-rUS/strings.xml": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="app_name" translatable="false">Iris</string>
+    <string name="hello">Hello, %1$s!</string>
+    <string name="quote">Don\'t say \"hi\"\nhere</string>
+    <plurals name="emails">
+        <item quantity="one">%d email</item>
+        <item quantity="other">%d emails</item>
+    </plurals>
+</resources>`)},
+		"res/values-el/strings.xml": &fstest.MapFile{Data: []byte(`<resources>
+    <string name="hello">Γειά σου, %1$s!</string>
+</resources>`)},
+		"res/values-b+sr+Latn/strings.xml": &fstest.MapFile{Data: []byte(`<resources>
+    <string name="hello">Zdravo, %1$s!</string>
+</resources>`)},
+	}
+
+	loader, err := FS(fileSystem, "res/*/strings.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "el-GR", "sr-Latn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "hello", []interface{}{"kataras"}, "Hello, kataras!"},
+		{"el-GR", "hello", []interface{}{"kataras"}, "Γειά σου, kataras!"},
+		{"sr-Latn", "hello", []interface{}{"kataras"}, "Zdravo, kataras!"},
+		{"en-US", "quote", nil, "Don't say \"hi\"\nhere"},
+		{"en-US", "emails", []interface{}{PluralCount(1)}, "1 email"},
+		{"en-US", "emails", []interface{}{PluralCount(2)}, "2 emails"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] expected %q but got %q", i, tt.expected, got)
+		}
+	}
+
+	if i18N.Exists("en-US", "app_name") {
+		t.Fatalf("expected untranslatable strings to be skipped")
+	}
+}
+
+func TestLoadAppleStrings(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"Resources/en.lproj/Localizable.strings": &fstest.MapFile{Data: []byte(`/* The greeting. */
+"hello" = "Hello, %@!";
+// Positional arguments.
+"order" = "%2$@ before %1$@";
+"quote" = "Say \"hi\"\n";
+nav.home = "Home";
+`)},
+		"Resources/en.lproj/Localizable.stringsdict": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>emails</key>
+    <dict>
+        <key>NSStringLocalizedFormatKey</key>
+        <string>You have %#@count@</string>
+        <key>count</key>
+        <dict>
+            <key>NSStringFormatSpecTypeKey</key>
+            <string>NSStringPluralRuleType</string>
+            <key>NSStringFormatValueTypeKey</key>
+            <string>ld</string>
+            <key>one</key>
+            <string>%ld email</string>
+            <key>other</key>
+            <string>%ld emails</string>
+        </dict>
+    </dict>
+</dict>
+</plist>`)},
+		"Resources/pt_BR.lproj/Localizable.strings": &fstest.MapFile{Data: []byte(`"hello" = "Olá, %@!";`)},
+		"Resources/Base.lproj/Main.strings":         &fstest.MapFile{Data: []byte(`"hello" = "Base";`)},
+	}
+
+	loader, err := FS(fileSystem, "Resources/*/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i18N, err := New(loader, "en-US", "pt-BR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "hello", []interface{}{"kataras"}, "Hello, kataras!"},
+		{"pt-BR", "hello", []interface{}{"kataras"}, "Olá, kataras!"},
+		{"en-US", "order", []interface{}{"a", "b"}, "b before a"},
+		{"en-US", "quote", nil, "Say \"hi\"\n"},
+		{"en-US", "nav.home", nil, "Home"},
+	}
+
+	for i, tt := range tests {
+		if got := i18N.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Fatalf("[%d] expected %q but got %q", i, tt.expected, got)
+		}
+	}
+
+	for count, expected := range map[int]string{1: "You have 1 email", 5: "You have 5 emails"} {
+		if got := i18N.TrPlural("en-US", "emails", count); got != expected {
+			t.Fatalf("expected %q but got %q", expected, got)
+		}
+	}
 }
@@ -0,0 +1,49 @@
+package i18n
+
+// LookupStatus reports how a translation lookup was served, see `LookupResult`.
+type LookupStatus uint8
+
+const (
+	// LookupHit is reported when the message was found on the requested language.
+	LookupHit LookupStatus = iota
+	// LookupFallback is reported when the message was served by another language,
+	// e.g. the default one, because the requested language or its key was not found.
+	LookupFallback
+	// LookupMiss is reported when the message was not found on any language.
+	LookupMiss
+)
+
+// String returns the name of the status, e.g. "hit".
+func (s LookupStatus) String() string {
+	switch s {
+	case LookupHit:
+		return "hit"
+	case LookupFallback:
+		return "fallback"
+	default:
+		return "miss"
+	}
+}
+
+// LookupResult is the result of a translation lookup, see `I18n.OnLookup`.
+type LookupResult struct {
+	// Status reports whether the message was a hit, a fallback or a miss.
+	Status LookupStatus
+	// Lang is the language which served the message, empty on `LookupMiss`.
+	Lang string
+}
+
+// newLookupResult returns the result of a lookup of the "requested" locale,
+// "matched" reports whether the input language was matched.
+func newLookupResult(requested, served *Locale, matched bool) LookupResult {
+	if served == nil {
+		return LookupResult{Status: LookupMiss}
+	}
+
+	result := LookupResult{Status: LookupHit, Lang: served.Language()}
+	if !matched || requested == nil || served.Index() != requested.Index() {
+		result.Status = LookupFallback
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// LogMissingOnce returns a `MessageFunc` which calls the "log" function
+// once per missing language and key, not on every request, e.g. to log
+// the missing translations to a structured logger:
+//
+//	i18N.DefaultMessageFunc = i18n.LogMissingOnce(func(lang, key string) {
+//		logger.Warn("missing translation", "lang", lang, "key", key)
+//	}, i18n.HumanizeKeyMessageFunc)
+//
+// The "lang" is the matched language, or "und" if not matched,
+// so the logged keys are bounded by the registered languages, not by the client's input.
+// The returned message is the result of the "fallback" one, an empty string if nil.
+func LogMissingOnce(log func(lang, key string), fallback MessageFunc) MessageFunc {
+	var logged sync.Map
+
+	return func(langInput, langMatched, key string, args ...interface{}) string {
+		lang := langMatched
+		if lang == "" {
+			lang = language.Und.String()
+		}
+
+		if _, loaded := logged.LoadOrStore(lang+"\x00"+key, struct{}{}); !loaded && log != nil {
+			log(lang, key)
+		}
+
+		if fallback == nil {
+			return ""
+		}
+
+		return fallback(langInput, langMatched, key, args...)
+	}
+}
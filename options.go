@@ -0,0 +1,88 @@
+package i18n
+
+import "fmt"
+
+// Option sets a field of the `I18n` on `NewWithOptions`, before its first load,
+// so it is never modified while the requests are served.
+type Option func(*I18n)
+
+// WithLanguages registers the "languages", see the "languages" of the `New` function.
+func WithLanguages(languages ...string) Option {
+	return func(i *I18n) {
+		i.languages = append(i.languages, languages...)
+	}
+}
+
+// WithDefault sets the default language explicitly, which is the first of the `WithLanguages`
+// ones otherwise. The order of the languages is kept. It should be one of the registered languages
+// or, if the languages are not registered, one of the loaded ones.
+func WithDefault(lang string) Option {
+	return func(i *I18n) {
+		i.defaultLang = lang
+	}
+}
+
+// WithDefaultMessageFunc sets the `I18n.DefaultMessageFunc` field.
+func WithDefaultMessageFunc(fn MessageFunc) Option {
+	return func(i *I18n) {
+		i.DefaultMessageFunc = fn
+	}
+}
+
+// WithStrict sets the `I18n.Strict` field to true.
+func WithStrict() Option {
+	return func(i *I18n) {
+		i.Strict = true
+	}
+}
+
+// WithCookie sets the `I18n.Cookie` field, the name of the language cookie.
+func WithCookie(name string) Option {
+	return func(i *I18n) {
+		i.Cookie = name
+	}
+}
+
+// WithURLParameter sets the `I18n.URLParameter` field, the name of the language URL query parameter.
+func WithURLParameter(name string) Option {
+	return func(i *I18n) {
+		i.URLParameter = name
+	}
+}
+
+// WithSubdomain sets the `I18n.Subdomain` field to true.
+func WithSubdomain() Option {
+	return func(i *I18n) {
+		i.Subdomain = true
+	}
+}
+
+// NewWithOptions same as `New` but it accepts options instead of the languages,
+// e.g. NewWithOptions(loader, WithLanguages("en-US", "el-GR"), WithCookie("lang"), WithStrict()).
+// The options are applied before the first load, e.g. the `WithDefaultMessageFunc`
+// is used by the loaded locales too.
+func NewWithOptions(loader Loader, options ...Option) (*I18n, error) {
+	i := newI18n(loader)
+	for _, opt := range options {
+		opt(i)
+	}
+
+	i.setMatcher(i.languages)
+
+	// the default language of the registered ones is set before the loading,
+	// so the loaders, e.g. the Lazy ones, load it first. Otherwise it is one of the loaded ones.
+	setDefaultFirst := i.defaultLang != "" && len(i.matcher.Languages) > 0
+	if setDefaultFirst && !i.setDefaultIndex(i.defaultLang) {
+		return nil, fmt.Errorf("%w: default: %s", ErrLanguageNotMatched, i.defaultLang)
+	}
+
+	if err := i.Reload(); err != nil {
+		return nil, err
+	}
+
+	if i.defaultLang != "" && !setDefaultFirst && !i.setDefaultIndex(i.defaultLang) {
+		return nil, fmt.Errorf("%w: default: %s", ErrLanguageNotMatched, i.defaultLang)
+	}
+
+	return i, nil
+}
@@ -0,0 +1,150 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// unmarshalProperties decodes a Java-style .properties file.
+// It supports comment lines (starting with '#' or '!'),
+// "key=value", "key:value" and "key value" pairs,
+// line continuations with a trailing backslash and
+// the escape sequences, including the "\uXXXX" unicode ones.
+//
+// Dotted keys, e.g. "nav.home", are stored as they are,
+// which is the same lookup path a nested yaml value produces.
+func unmarshalProperties(data []byte, v interface{}) error {
+	m := *v.(*map[string]interface{})
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// join the continuation lines.
+		for endsWithContinuation(line) && scanner.Scan() {
+			lineNumber++
+			line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+		line = strings.TrimSuffix(line, `\`) // a continuation on the last line.
+
+		rawKey, rawValue := splitProperty(line)
+
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return fmt.Errorf("properties: line %d: %w", lineNumber, err)
+		}
+
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return fmt.Errorf("properties: line %d: %w", lineNumber, err)
+		}
+
+		m[key] = value
+	}
+
+	return scanner.Err()
+}
+
+// endsWithContinuation reports whether the line ends
+// with an odd number of backslashes.
+func endsWithContinuation(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line to its raw key and value.
+// The key ends on the first unescaped '=', ':' or white space.
+func splitProperty(line string) (string, string) {
+	idx := len(line)
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		if ch == '\\' {
+			i++ // skip the escaped character.
+			continue
+		}
+
+		if ch == '=' || ch == ':' || ch == ' ' || ch == '\t' || ch == '\f' {
+			idx = i
+			break
+		}
+	}
+
+	key, value := line[:idx], line[idx:]
+
+	value = strings.TrimLeft(value, " \t\f")
+	if value != "" && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+
+	return key, value
+}
+
+func unescapeProperty(s string) (string, error) {
+	if strings.IndexByte(s, '\\') == -1 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch != '\\' || i == len(s)-1 {
+			b.WriteByte(ch)
+			continue
+		}
+
+		i++
+		switch ch = s[i]; ch {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\uXXXX encoding: %q", s[i-1:])
+			}
+
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uXXXX encoding: %q", s[i-1:i+5])
+			}
+			i += 4
+
+			// characters outside the BMP are encoded as UTF-16 surrogate pairs.
+			if utf16.IsSurrogate(rune(r)) && i+6 < len(s) && s[i+1] == '\\' && s[i+2] == 'u' {
+				if r2, err := strconv.ParseUint(s[i+3:i+7], 16, 32); err == nil {
+					if dec := utf16.DecodeRune(rune(r), rune(r2)); dec != unicode.ReplacementChar {
+						r = uint64(dec)
+						i += 6
+					}
+				}
+			}
+
+			b.WriteRune(rune(r))
+		default: // escaped separators, backslashes and any other character.
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,29 @@
+package i18n
+
+import "fmt"
+
+// NewStub returns a new `I18n` instance without any translations, e.g. for the handler tests,
+// its `Tr`, `GetMessage` and the rest methods return the key itself,
+// followed by its arguments if any, e.g. "hello [Maria 2]",
+// so the assertions can target the keys instead of the translated text.
+//
+// The "defaultLang" is its only language, e.g. "en-US", it panics if that is not a valid language code.
+func NewStub(defaultLang string) *I18n {
+	i, err := NewWithOptions(KV(LangMap{defaultLang: Map{}}),
+		WithLanguages(defaultLang),
+		WithDefaultMessageFunc(stubMessage))
+	if err != nil {
+		panic(fmt.Sprintf("i18n: stub: %v", err))
+	}
+
+	return i
+}
+
+// stubMessage is the `MessageFunc` of the `NewStub`.
+func stubMessage(langInput, langMatched, key string, args ...interface{}) string {
+	if len(args) == 0 {
+		return key
+	}
+
+	return fmt.Sprintf("%s %v", key, args)
+}
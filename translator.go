@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+)
+
+// Translator is the interface which the `I18n` implements,
+// so the code can depend on it instead of the concrete `I18n`, e.g. to inject a mock in tests.
+//
+// See `NopTranslator` too.
+type Translator interface {
+	// Tr returns the translated message of the "format" (or key) on the "lang" language.
+	Tr(lang, format string, args ...interface{}) string
+	// TrContext returns the translated message of the "format" (or key) on the language of the "ctx".
+	TrContext(ctx context.Context, format string, args ...interface{}) string
+	// GetMessage returns the translated message of the "format" (or key) on the language of the "r" request.
+	GetMessage(r *http.Request, format string, args ...interface{}) string
+}
+
+var (
+	_ Translator = (*I18n)(nil)
+	_ Translator = NopTranslator{}
+)
+
+// NopTranslator is a `Translator` which returns the "format" (or key) as it is,
+// e.g. for the tests which do not care about the translated text.
+type NopTranslator struct{}
+
+// Tr returns the "format" as it is.
+func (NopTranslator) Tr(lang, format string, args ...interface{}) string {
+	return format
+}
+
+// TrContext returns the "format" as it is.
+func (NopTranslator) TrContext(ctx context.Context, format string, args ...interface{}) string {
+	return format
+}
+
+// GetMessage returns the "format" as it is.
+func (NopTranslator) GetMessage(r *http.Request, format string, args ...interface{}) string {
+	return format
+}
@@ -0,0 +1,228 @@
+package i18n
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay is the time to wait after the last file event before reloading,
+// it debounces rapid successive writes (e.g. editors writing temp files).
+const watchDelay = 100 * time.Millisecond
+
+// Watch is like `Glob` but it keeps watching the directories of the locale files
+// and reloads the translations whenever a file that matches the "globPattern"
+// is created, modified or removed.
+// If the reload fails, e.g. on a half-written file, the previous translations are kept.
+//
+// Call the `I18n.Close` method to stop watching.
+//
+// See `Glob`, `New` and `LoaderConfig` too.
+func Watch(globPattern string, options ...LoaderConfig) Loader {
+	globPattern = filepath.Clean(globPattern)
+	if _, err := filepath.Match(globPattern, ""); err != nil {
+		panic(err)
+	}
+
+	w := &watcher{
+		globPattern: globPattern,
+		dirPattern:  filepath.Dir(globPattern),
+		options:     options,
+	}
+
+	return w.load
+}
+
+// watcher holds the patterns and the options of a `Watch` loader,
+// it is shared by the I18n instances of the loader,
+// each one keeps its own fileWatcher, see `watchLocalizer.watch`.
+type watcher struct {
+	globPattern string
+	dirPattern  string
+	options     []LoaderConfig
+}
+
+// fileWatcher watches the directories of the locale files of an I18n instance
+// and calls its "reload" after a debounced file change.
+type fileWatcher struct {
+	*watcher
+
+	mu     sync.Mutex // guards fsw and closed.
+	fsw    *fsnotify.Watcher
+	closed bool
+}
+
+// watchableLocalizer is implemented by the localizers
+// which should call "reload" on changes, see `I18n.Reload`.
+// The "prev" is the localizer which is replaced, nil on the first load.
+type watchableLocalizer interface {
+	watch(prev Localizer, reload func() error) error
+}
+
+// watchLocalizer is the Localizer which the `Watch` loader returns.
+type watchLocalizer struct {
+	Localizer
+	watcher     *watcher
+	fileWatcher *fileWatcher
+	assetNames  []string
+}
+
+// watch keeps the file watcher of the "prev" localizer, of the same I18n instance,
+// or starts a new one on the first load, and watches the directories of the loaded files.
+// A closed file watcher is not started again.
+func (l *watchLocalizer) watch(prev Localizer, reload func() error) error {
+	if p, ok := prev.(*watchLocalizer); ok && p.fileWatcher != nil {
+		l.fileWatcher = p.fileWatcher
+	} else {
+		fw, err := l.watcher.start(reload)
+		if err != nil {
+			return err
+		}
+
+		l.fileWatcher = fw
+	}
+
+	return l.fileWatcher.addDirs(l.assetNames)
+}
+
+// Close stops the file watcher.
+func (l *watchLocalizer) Close() error {
+	if l.fileWatcher == nil {
+		return nil
+	}
+
+	return l.fileWatcher.Close()
+}
+
+func (w *watcher) load(m *Matcher) (Localizer, error) {
+	assetNames, err := filepath.Glob(w.globPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	localizer, err := load(assetNames, os.ReadFile, w.options...)(m)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &watchLocalizer{
+		Localizer:  localizer,
+		watcher:    w,
+		assetNames: assetNames,
+	}
+	return l, nil
+}
+
+// start starts a new file watcher goroutine, "reload" is called
+// after a debounced file change.
+func (w *watcher) start(reload func() error) (*fileWatcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &fileWatcher{watcher: w, fsw: fsw}
+	go fw.watch(reload)
+	return fw, nil
+}
+
+// addDirs watches the directories of the locale files and their parents,
+// so new language directories are noticed too. Adding a watched path is a no-op.
+// It does nothing after Close.
+func (fw *fileWatcher) addDirs(assetNames []string) error {
+	dirs, _ := filepath.Glob(fw.dirPattern)
+	for _, assetName := range assetNames {
+		dirs = append(dirs, filepath.Dir(assetName))
+	}
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if fw.closed {
+		return nil
+	}
+
+	var errs []error
+	for _, dir := range dirs {
+		if err := fw.fsw.Add(dir); err != nil {
+			errs = append(errs, err)
+		}
+
+		if parent := filepath.Dir(dir); parent != dir {
+			if err := fw.fsw.Add(parent); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (fw *fileWatcher) watch(reload func() error) {
+	timer := time.NewTimer(watchDelay)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	// the channels are closed by Close.
+	events, errs := fw.fsw.Events, fw.fsw.Errors
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if !fw.matches(event.Name) {
+				continue
+			}
+
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(watchDelay)
+		case _, ok := <-errs:
+			if !ok {
+				return
+			}
+		case <-timer.C:
+			if err := reload(); err != nil {
+				// the previous localizer is kept.
+				log.Printf("i18n: watch: reload: %v", err)
+			}
+		}
+	}
+}
+
+func (w *watcher) matches(name string) bool {
+	if ok, _ := filepath.Match(w.globPattern, name); ok {
+		return true
+	}
+
+	// a language directory was created or removed.
+	ok, _ := filepath.Match(w.dirPattern, name)
+	return ok
+}
+
+// Close stops the watcher goroutine.
+func (fw *fileWatcher) Close() error {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	if fw.closed {
+		return nil
+	}
+
+	fw.closed = true
+	return fw.fsw.Close()
+}
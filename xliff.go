@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// The XLIFF 2.0 document elements which are decoded by the `unmarshalXLIFF`.
+type (
+	xliffDocument struct {
+		Version string       `xml:"version,attr"`
+		TrgLang string       `xml:"trgLang,attr"`
+		Files   []xliffGroup `xml:"file"`
+	}
+
+	// xliffGroup is a <file> or a <group> element.
+	xliffGroup struct {
+		ID     string       `xml:"id,attr"`
+		Groups []xliffGroup `xml:"group"`
+		Units  []xliffUnit  `xml:"unit"`
+	}
+
+	xliffUnit struct {
+		ID       string         `xml:"id,attr"`
+		Segments []xliffSegment `xml:"segment"`
+	}
+
+	xliffSegment struct {
+		State  string `xml:"state,attr"`
+		Target *struct {
+			Text string `xml:",chardata"`
+		} `xml:"target"`
+	}
+)
+
+// xliffStateInitial is the state of the segments which are not translated yet.
+const xliffStateInitial = "initial"
+
+// unmarshalXLIFF decodes an XLIFF 2.0 file, the ".xlf" and ".xliff" ones.
+// The id of a <unit> is the key and the <target> elements of its segments are the value,
+// the ids of the parent <group> elements are the key's prefix, e.g. "nav.home".
+//
+// The units without a target or with a segment of the "initial" state,
+// e.g. a new one which needs translation, are skipped so they fallback to the default language.
+// The "translated", "reviewed" and "final" states, or no state, are loaded.
+func unmarshalXLIFF(data []byte, v interface{}) error {
+	var doc xliffDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("xliff: %w", err)
+	}
+
+	if doc.Version != "" && !strings.HasPrefix(doc.Version, "2.") {
+		return fmt.Errorf("xliff: unsupported version: %s", doc.Version)
+	}
+
+	m := *v.(*map[string]interface{})
+	for _, file := range doc.Files {
+		setXLIFFGroup(m, "", file)
+	}
+
+	return nil
+}
+
+func setXLIFFGroup(m map[string]interface{}, prefix string, group xliffGroup) {
+	for _, child := range group.Groups {
+		childPrefix := prefix
+		if child.ID != "" {
+			childPrefix += child.ID + "."
+		}
+
+		setXLIFFGroup(m, childPrefix, child)
+	}
+
+	for _, unit := range group.Units {
+		if unit.ID == "" {
+			continue
+		}
+
+		if value, ok := xliffUnitTarget(unit); ok {
+			m[prefix+unit.ID] = value
+		}
+	}
+}
+
+// xliffUnitTarget returns the joined targets of the "unit" segments
+// and false if any of them is not translated.
+func xliffUnitTarget(unit xliffUnit) (string, bool) {
+	if len(unit.Segments) == 0 {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, segment := range unit.Segments {
+		if segment.Target == nil || segment.State == xliffStateInitial {
+			return "", false
+		}
+
+		b.WriteString(segment.Target.Text)
+	}
+
+	return b.String(), true
+}
+
+// xliffTargetLanguage returns the trgLang attribute of an XLIFF file, if any.
+func xliffTargetLanguage(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "trgLang" {
+					return attr.Value
+				}
+			}
+
+			return "" // the root element.
+		}
+	}
+}
+
+func isXLIFF(fileName string) bool {
+	return strings.HasSuffix(fileName, ".xlf") || strings.HasSuffix(fileName, ".xliff")
+}